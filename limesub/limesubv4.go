@@ -1,17 +1,28 @@
 package main
 
 import (
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
+	"flag"
 	"fmt"
 	"html"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 	"github.com/sqweek/dialog"
 )
 
@@ -26,6 +37,59 @@ const (
 	defaultPlayResY = 720.0
 )
 
+// ---------- Opsi dari command line ----------
+// cliOptions diisi oleh flag di main(). Nilai default menjaga perilaku
+// drag & drop tetap sama seperti tanpa flag.
+type cliOptions struct {
+	DetectSign      bool          // pakai detektor tanda multi-sinyal (durasi/posisi/tanda baca)
+	SignWeights     signWeights   // bobot untuk DetectSign
+	Check           bool          // hanya diagnostik file .ass, tidak menulis output
+	To              string        // format output: "ass" (default), "vtt", "csv", "tsv", "txt" atau "srt"
+	FPS             float64       // fps untuk timecode berbasis frame jika metadata tidak ada (0 = default)
+	FPSDetect       bool          // pakai fps dari metadata file (TTML ttp:frameRate) jika ada
+	YTKaraoke       bool          // JSON YouTube: timing per kata jadi \k, bukan level kalimat
+	YTCoalesce      bool          // JSON YouTube: gabungkan rolling caption yang tumpang tindih
+	Verbose         bool          // -v: log langkah (deteksi, skala, merge) ke stderr
+	Benchmark       bool          // cetak waktu per fase (parse/transform/serialize)
+	NoTanda         bool          // matikan heuristik style "tanda" sepenuhnya
+	GroupTanda      bool          // kumpulkan semua baris tanda di atas (perilaku lama)
+	Annotate        bool          // tulis Comment: berisi nomor & timing cue sumber sebelum tiap Dialogue
+	FixTimes        bool          // tukar balik start/end cue yang terbalik sebelum processSRT
+	MinDuration     time.Duration // buang cue yang lebih pendek dari ini (0 = hanya yang durasinya nol/negatif)
+	MinDisplay      time.Duration // perpanjang cue yang lebih pendek dari ini (0 = nonaktif)
+	Sync            []syncPoint   // titik acuan -sync untuk remap waktu linear
+	TimeScale       float64       // remap waktu: t' = t*TimeScale + TimeOffset (0 = nonaktif)
+	TimeOffset      float64       // offset remap waktu dalam ms
+	CRLF            bool          // akhir baris output CRLF (default ikut format, lihat lineEndingFlags)
+	BOM             bool          // tulis BOM UTF-8 di awal output
+	Strict          bool          // buang cue SRT dengan baris timing rusak (default: waktu jadi nol)
+	PreserveSpacing bool          // jangan ringkas spasi beruntun di teks cue
+	TimeUnit        string        // satuan waktu Custom XML: auto, cs, ms, s
+	MergeGap        time.Duration // gabungkan cue teks+style sama yang jedanya di bawah ini (0 = hanya yang bersambung)
+	DefaultFX       string        // override di awal tiap baris style Default ("" = tanpa efek)
+	Track           int           // nomor track subtitle MKV (0 = track teks pertama)
+	LineBreak       string        // pemisah baris teks gabungan: "hard" (\N) atau "soft" (\n)
+	RTL             bool          // perlakukan semua cue sebagai RTL (default: deteksi per cue)
+	RTLq2           bool          // tambahkan {\q2} di cue RTL
+	WithTimestamps  bool          // -to txt: waktu mulai di depan tiap baris
+	Flatten         bool          // buang semua posisi, semua baris jadi dialog Default di bawah tengah
+	SRTCoords       bool          // ubah koordinat SRT (X1:.. Y1:..) jadi \an7\pos
+	Lang            string        // TTML multibahasa: bahasa (xml:lang) yang diambil ("" = bahasa pertama)
+	Timing          string        // import cue sheet: file asal yang timing/style/header-nya dipakai
+}
+
+var opts = cliOptions{
+	SignWeights: defaultSignWeights,
+	To:          "ass",
+	FPSDetect:   true,
+	TimeUnit:    "auto",
+	DefaultFX:   defaultDialogueFX,
+	LineBreak:   "hard",
+}
+
+// defaultDialogueFX: efek bawaan baris dialog limenime (blur tepi + fade-out singkat).
+const defaultDialogueFX = `{\blur3}{\fad(00,40)}`
+
 // ---------- Utility helpers ----------
 func parseFloatSafe(s string, def float64) float64 {
 	s = strings.TrimSpace(s)
@@ -62,463 +126,727 @@ func splitNPreserveTrailing(s string, sep rune, n int) []string {
 	return parts
 }
 
-// scaleFloat formats scaled value: integer without decimals, otherwise 2 decimals trimmed trailing zeros.
-func scaleFloatFormat(v float64) string {
-	// if v is close to int:
-	if float64(int64(v)) == v {
-		return fmt.Sprintf("%d", int64(v))
-	}
-	// else 2 decimals but trim trailing zeroes
-	s := fmt.Sprintf("%.2f", v)
-	s = strings.TrimRight(s, "0")
-	s = strings.TrimRight(s, ".")
-	return s
-}
-
-// scaleXYList: scale alternating numbers in a string (used for vector paths)
-func scaleXYList(s string, ratioX, ratioY float64) string {
-	re := regexp.MustCompile(`-?\d+(\.\d+)?`)
-	indices := re.FindAllStringIndex(s, -1)
-	if len(indices) == 0 {
+// replaceSubmatch seperti ReplaceAllStringFunc, tapi fn langsung menerima
+// submatch (tanpa FindStringSubmatch ulang per match) dan hasilnya disusun
+// berdasarkan posisi match dalam satu strings.Builder.
+func replaceSubmatch(re *regexp.Regexp, s string, fn func(sub []string) string) string {
+	locs := re.FindAllStringSubmatchIndex(s, -1)
+	if locs == nil {
 		return s
 	}
-	out := bytes.NewBuffer(nil)
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/4)
 	last := 0
-	count := 0
-	for _, idx := range indices {
-		out.WriteString(s[last:idx[0]])
-		num := s[idx[0]:idx[1]]
-		f, err := strconv.ParseFloat(num, 64)
-		if err != nil {
-			out.WriteString(num)
-		} else {
-			if count%2 == 0 {
-				out.WriteString(scaleFloatFormat(f * ratioX))
-			} else {
-				out.WriteString(scaleFloatFormat(f * ratioY))
+	for _, loc := range locs {
+		sb.WriteString(s[last:loc[0]])
+		sub := make([]string, len(loc)/2)
+		for k := range sub {
+			if loc[2*k] >= 0 {
+				sub[k] = s[loc[2*k]:loc[2*k+1]]
 			}
 		}
-		last = idx[1]
-		count++
+		sb.WriteString(fn(sub))
+		last = loc[1]
 	}
-	out.WriteString(s[last:])
-	return out.String()
+	sb.WriteString(s[last:])
+	return sb.String()
 }
 
-// scaleNumberInString: replace a number string with scaled value
-func scaleNumberString(numStr string, scale float64) string {
-	f, err := strconv.ParseFloat(numStr, 64)
-	if err != nil {
-		return numStr
+// replaceParenArgs seperti replaceSubmatch untuk re yang berakhir di "(":
+// isi kurung sampai ")" pertama ditambahkan sebagai submatch terakhir dan
+// seluruh tag sampai ")" diganti hasil fn. Penutupnya dicari dengan IndexByte,
+// karena ([^)]*) di regex jadi kuadratik pada vector clip ribuan angka.
+func replaceParenArgs(re *regexp.Regexp, s string, fn func(sub []string) string) string {
+	var sb strings.Builder
+	for {
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			break
+		}
+		end := strings.IndexByte(s[loc[1]:], ')')
+		if end < 0 {
+			// tanpa ")" tidak ada tag lengkap lagi sesudahnya
+			break
+		}
+		end += loc[1]
+		sub := make([]string, len(loc)/2+1)
+		for k := 0; k < len(loc)/2; k++ {
+			if loc[2*k] >= 0 {
+				sub[k] = s[loc[2*k]:loc[2*k+1]]
+			}
+		}
+		sub[len(sub)-1] = s[loc[1]:end]
+		sb.WriteString(s[:loc[0]])
+		sb.WriteString(fn(sub))
+		s = s[end+1:]
 	}
-	return scaleFloatFormat(f * scale)
+	sb.WriteString(s)
+	return sb.String()
 }
 
-// ---------- Tag scaling (best-effort) ----------
-func scaleTags(content string, ratioX, ratioY float64) string {
-	s := content
+// rePlaceholder: penanda "\x00N\x00" untuk potongan teks yang disisihkan sementara.
+var rePlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
 
-	// \fs and \fsp -> scale by ratioY
-	reFs := regexp.MustCompile(`\\fs(-?\d+(\.\d+)?)`)
-	s = reFs.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reFs.FindStringSubmatch(m)
-		return `\fs` + scaleNumberString(sub[1], ratioY)
-	})
-	reFsp := regexp.MustCompile(`\\fsp(-?\d+(\.\d+)?)`)
-	s = reFsp.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reFsp.FindStringSubmatch(m)
-		return `\fsp` + scaleNumberString(sub[1], ratioY)
+// restorePlaceholders mengembalikan saved[N] ke tiap penanda dalam satu pass.
+func restorePlaceholders(s string, saved []string) string {
+	if len(saved) == 0 {
+		return s
+	}
+	return replaceSubmatch(rePlaceholder, s, func(sub []string) string {
+		if i, err := strconv.Atoi(sub[1]); err == nil && i < len(saved) {
+			return saved[i]
+		}
+		return sub[0]
 	})
+}
 
-	// \pos(x,y)
-	rePos := regexp.MustCompile(`\\pos\(\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*\)`)
-	s = rePos.ReplaceAllStringFunc(s, func(m string) string {
-		sub := rePos.FindStringSubmatch(m)
-		x := scaleNumberString(sub[1], ratioX)
-		y := scaleNumberString(sub[3], ratioY)
-		return `\pos(` + x + "," + y + `)`
-	})
+// formatScaled: format angka hasil skala seperti Aegisub — dibulatkan 3 desimal,
+// nol di belakang dibuang (960, 0.5, 33.333), tanpa "-0".
+func formatScaled(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 {
+		v = 0 // hindari "-0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
 
-	// \org(x,y)
-	reOrg := regexp.MustCompile(`\\org\(\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*\)`)
-	s = reOrg.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reOrg.FindStringSubmatch(m)
-		x := scaleNumberString(sub[1], ratioX)
-		y := scaleNumberString(sub[3], ratioY)
-		return `\org(` + x + "," + y + `)`
-	})
+// ======================================
+// 🔹 Resample ASS (gaya Aegisub "Resample Resolution", mode Stretch)
+// ======================================
 
-	// \move(x1,y1,x2,y2[,t1,t2])
-	reMove := regexp.MustCompile(`\\move\(\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)([^)]*)\)`)
-	s = reMove.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reMove.FindStringSubmatch(m)
-		x1 := scaleNumberString(sub[1], ratioX)
-		y1 := scaleNumberString(sub[3], ratioY)
-		x2 := scaleNumberString(sub[5], ratioX)
-		y2 := scaleNumberString(sub[7], ratioY)
-		tail := sub[8]
-		return `\move(` + x1 + "," + y1 + "," + x2 + "," + y2 + tail + `)`
-	})
+// Format default [V4+ Styles] / [Events] jika file tidak punya baris Format:
+var (
+	defaultStyleFormat = []string{
+		"name", "fontname", "fontsize", "primarycolour", "secondarycolour", "outlinecolour", "backcolour",
+		"bold", "italic", "underline", "strikeout", "scalex", "scaley", "spacing", "angle",
+		"borderstyle", "outline", "shadow", "alignment", "marginl", "marginr", "marginv", "encoding",
+	}
+	defaultEventFormat = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+)
 
-	// \clip(...) and \iclip(...)
-	reClip := regexp.MustCompile(`\\(i?clip)\(\s*([^\)]*)\s*\)`)
-	s = reClip.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reClip.FindStringSubmatch(m)
-		if len(sub) < 3 {
-			return m
+// knownASSSections: nama section ASS standar (huruf kecil, tanpa kurung siku).
+var knownASSSections = map[string]bool{
+	"script info": true, "v4+ styles": true, "v4 styles": true, "events": true,
+	"fonts": true, "graphics": true, "aegisub project garbage": true, "aegisub extradata": true,
+}
+
+// Option mengatur langkah tambahan ResampleASS di luar skala resolusi.
+type Option func(*resampleConfig)
+
+type resampleConfig struct {
+	fontName   string // jika diisi: font semua style dan tag \fn diganti ke font ini
+	extraStyle string // baris Style: tambahan di akhir [V4+ Styles] (tidak diduplikasi)
+}
+
+// WithFontName mengganti Fontname di semua style dan \fn di override.
+func WithFontName(name string) Option {
+	return func(c *resampleConfig) { c.fontName = name }
+}
+
+// WithExtraStyle menambahkan baris Style: di akhir [V4+ Styles] jika belum ada.
+func WithExtraStyle(line string) Option {
+	return func(c *resampleConfig) { c.extraStyle = line }
+}
+
+// resampler menyimpan rasio skala satu kali resample. Aturannya mengikuti
+// ResampleResolution Aegisub:
+//   - rx/ry: posisi (\pos, \move, \org, \clip, drawing), margin (int), \xbord/\ybord dst.
+//   - rm = sqrt(rx*ry): ukuran absolut dua arah (\bord, \shad, \be, \blur)
+//   - ar = rasio aspek baru / lama: ScaleX style dan \fscx; ScaleY/\fscy tidak diubah
+//   - Fontsize style dan \fs ikut ry (style dibulatkan ke int), Outline/Shadow style ikut ry
+//
+// MarginV sengaja tidak melihat alignment (\an/\a atau style): untuk \an7-9
+// margin diukur dari tepi atas, untuk \an1-3 dari tepi bawah, dan pada mode
+// Stretch kedua tepi itu ikut diskalakan ry, jadi v*ry menaruh baris di posisi
+// relatif yang sama (mis. \an8 MarginV 20 @720p → 30 @1080p, tetap 2.8% dari
+// atas). Baris \an4-6 mengabaikan MarginV, tapi tetap diskalakan supaya benar
+// kalau alignment-nya diubah lewat override. Mode dengan offset (letterbox)
+// harus menambahkan offset tepi yang sesuai alignment di sini.
+type resampler struct {
+	rx, ry, rm, ar float64
+}
+
+var (
+	reResNum       = `(-?\d*\.?\d+)`
+	reResPos       = regexp.MustCompile(`\\pos\s*\(\s*` + reResNum + `\s*,\s*` + reResNum + `\s*(,[^)]*)?\)`)
+	reResOrg       = regexp.MustCompile(`\\org\s*\(\s*` + reResNum + `\s*,\s*` + reResNum + `\s*(,[^)]*)?\)`)
+	reResMove      = regexp.MustCompile(`\\move\s*\(\s*` + reResNum + `\s*,\s*` + reResNum + `\s*,\s*` + reResNum + `\s*,\s*` + reResNum + `([^)]*)\)`)
+	reResClip      = regexp.MustCompile(`\\(i?clip)\s*\(`) // hanya kepala; isi kurung lewat replaceParenArgs
+	reResClipScale = regexp.MustCompile(`^(\d+\s*,\s*)([A-Za-z].*)$`)
+	reResFscTag    = regexp.MustCompile(`\\fsc\s*` + reResNum) // \fsc gabungan x+y (bukan \fscx/\fscy)
+	reResSizeTag   = regexp.MustCompile(`\\(xbord|ybord|xshad|yshad|bord|shad|blur|be|fscx|fsp|fs|pbo)\s*` + reResNum)
+	reResFontName  = regexp.MustCompile(`\\fn[^\\}]*`)
+	reResDrawLevel = regexp.MustCompile(`\\p\s*(\d+)`)
+	reResAlpha     = regexp.MustCompile(`\\(?:alpha|[1-4]a)\s*&?[Hh]?[0-9A-Fa-f]*&?`)
+	reResTiming    = regexp.MustCompile(`\\(?:kt|kf|ko|[kK])\s*\d+|\\fade?\s*\([^)]*\)`) // karaoke & \fad/\fade 7-argumen
+)
+
+// almostOne: rasio skala praktis 1 (resolusi sumber = target).
+func almostOne(k float64) bool {
+	return math.Abs(k-1) < 1e-9
+}
+
+// identity: semua rasio 1, jadi resample tidak boleh mengubah angka apa pun.
+func (r resampler) identity() bool {
+	return almostOne(r.rx) && almostOne(r.ry) && almostOne(r.rm) && almostOne(r.ar)
+}
+
+func (r resampler) scale(s string, k float64) string {
+	if almostOne(k) {
+		// resolusi sama: angka ditulis apa adanya, jadi resample ulang tidak
+		// mengubah apa pun (termasuk teks drawing yang rusak seperti "..0")
+		return s
+	}
+	return formatScaled(parseFloatSafe(s, 0) * k)
+}
+
+// scalePath menskalakan angka x/y bergantian di path drawing/vector clip.
+func (r resampler) scalePath(path string) string {
+	i := 0
+	return r.scalePathAt(path, &i)
+}
+
+// scalePathAt seperti scalePath, tapi paritas x/y dibawa lewat *i supaya
+// drawing yang terpotong override block ({\p1}m 0 0 l 100{\c&HFF&}0 ...)
+// tetap berpasangan benar. Setiap huruf perintah (m, l, b, c, ...) mengembalikan
+// paritas ke x, jadi path rusak dengan jumlah angka ganjil tidak menukar x/y
+// di perintah berikutnya.
+func (r resampler) scalePathAt(path string, i *int) string {
+	// dipindai manual dalam satu pass: ReplaceAllStringFunc regex pada path
+	// ribuan angka jadi kuadratik (state backtracker di-reset per match)
+	var sb strings.Builder
+	sb.Grow(len(path) + len(path)/4)
+	for at := 0; at < len(path); {
+		c := path[at]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') {
+			*i = 0
+			sb.WriteByte(c)
+			at++
+			continue
 		}
-		fn := sub[1]
-		content := strings.TrimSpace(sub[2])
-		// vector path starts with letters like "m" or "M" or contains letters - scale numbers inside alternately
-		if len(content) > 0 && regexp.MustCompile(`^[a-zA-Z]`).MatchString(content) {
-			return `\` + fn + `(` + scaleXYList(content, ratioX, ratioY) + `)`
-		}
-		// otherwise treat as numbers separated by comma
-		nums := regexp.MustCompile(`-?\d+(\.\d+)?`).FindAllString(content, -1)
-		if len(nums) >= 4 {
-			out := content
-			// replace first four occurrences with scaled ones
-			out = regexp.MustCompile(regexp.QuoteMeta(nums[0])).ReplaceAllString(out, scaleNumberString(nums[0], ratioX))
-			out = regexp.MustCompile(regexp.QuoteMeta(nums[1])).ReplaceAllString(out, scaleNumberString(nums[1], ratioY))
-			out = regexp.MustCompile(regexp.QuoteMeta(nums[2])).ReplaceAllString(out, scaleNumberString(nums[2], ratioX))
-			out = regexp.MustCompile(regexp.QuoteMeta(nums[3])).ReplaceAllString(out, scaleNumberString(nums[3], ratioY))
-			return `\` + fn + `(` + out + `)`
-		}
-		// fallback: scale alternately
-		return `\` + fn + `(` + scaleXYList(content, ratioX, ratioY) + `)`
-	})
+		end := pathNumEnd(path, at)
+		if end < 0 {
+			sb.WriteByte(c)
+			at++
+			continue
+		}
+		m := path[at:end]
+		k := r.rx
+		if *i%2 == 1 {
+			k = r.ry
+		}
+		*i++
+		out := r.scale(m, k)
+		if m[0] == '-' && out[0] != '-' {
+			// "-0" jadi "0": tanpa pemisah, "10-0" akan menyatu jadi "100"
+			out = " " + out
+		}
+		sb.WriteString(out)
+		at = end
+	}
+	return sb.String()
+}
 
-	// pixel-like props -> vertical scale
-	rePixel := regexp.MustCompile(`\\(bord|shad|be|blur)(-?\d+(\.\d+)?)`)
-	s = rePixel.ReplaceAllStringFunc(s, func(m string) string {
-		sub := rePixel.FindStringSubmatch(m)
-		return `\` + sub[1] + scaleNumberString(sub[2], ratioY)
-	})
+// pathNumEnd: akhir angka (-?\d*\.?\d+) yang dimulai di s[at], atau -1 jika
+// tidak ada. "12." berhenti sebelum titik, "1.2.3" terbaca "1.2" lalu ".3".
+func pathNumEnd(s string, at int) int {
+	isDigit := func(j int) bool { return j < len(s) && '0' <= s[j] && s[j] <= '9' }
+	j := at
+	if j < len(s) && s[j] == '-' {
+		j++
+	}
+	start := j
+	for isDigit(j) {
+		j++
+	}
+	if j < len(s) && s[j] == '.' && isDigit(j+1) {
+		j += 2
+		for isDigit(j) {
+			j++
+		}
+		return j
+	}
+	if j == start {
+		return -1
+	}
+	return j
+}
+
+// drawLevel: angka level \p ("00" sama dengan 0 = drawing mati).
+func drawLevel(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
 
-	// margins: \margins(l,r,t,b)
-	reMargins := regexp.MustCompile(`\\margins\(\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*,\s*(-?\d+(\.\d+)?)\s*\)`)
-	s = reMargins.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reMargins.FindStringSubmatch(m)
-		l := scaleNumberString(sub[1], ratioX)
-		r := scaleNumberString(sub[3], ratioX)
-		t := scaleNumberString(sub[5], ratioY)
-		b := scaleNumberString(sub[7], ratioY)
-		return `\margins(` + l + "," + r + "," + t + "," + b + `)`
+// overrideBlock memproses isi satu blok {...} (tanpa kurung kurawal).
+// Tag di dalam \t(...) ikut terproses karena regex berjalan di seluruh isi blok.
+func (r resampler) overrideBlock(inner string) string {
+	if r.identity() {
+		// file sudah di resolusi target: isi blok dilewatkan byte demi byte
+		// (spasi, "0010", "20.0" tidak dinormalkan)
+		return inner
+	}
+	// alpha berupa nilai hex, bukan ukuran: disisihkan dulu supaya tidak pernah
+	// ikut tertangkap regex angka di bawah, lalu dikembalikan apa adanya
+	var alphas []string
+	keep := func(m string) string {
+		alphas = append(alphas, m)
+		return fmt.Sprintf("\x00%d\x00", len(alphas)-1)
+	}
+	inner = reResAlpha.ReplaceAllStringFunc(inner, keep)
+	// begitu juga waktu karaoke (\k, \kf, \ko, \kt) dan argumen alpha/waktu
+	// \fad/\fade: bukan koordinat, jadi tidak boleh ikut skala apa pun
+	inner = reResTiming.ReplaceAllStringFunc(inner, keep)
+	// \pos(x,y,...) — argumen ketiga dst. (keluaran tool yang rusak) dibiarkan
+	inner = replaceSubmatch(reResPos, inner, func(sub []string) string {
+		return `\pos(` + r.scale(sub[1], r.rx) + "," + r.scale(sub[2], r.ry) + sub[3] + ")"
 	})
-	// single margins
-	reMarginSingle := regexp.MustCompile(`\\margin([lrvbt])(-?\d+(\.\d+)?)`)
-	s = reMarginSingle.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reMarginSingle.FindStringSubmatch(m)
-		side := sub[1]
-		val := sub[2]
-		switch side {
-		case "l", "r":
-			return `\margin` + side + scaleNumberString(val, ratioX)
-		default:
-			return `\margin` + side + scaleNumberString(val, ratioY)
-		}
+	// \org(x,y) — bentuk 3-arg yang langka: hanya x,y yang diskalakan
+	inner = replaceSubmatch(reResOrg, inner, func(sub []string) string {
+		return `\org(` + r.scale(sub[1], r.rx) + "," + r.scale(sub[2], r.ry) + sub[3] + ")"
 	})
-
-	// \fax \fay
-	reFax := regexp.MustCompile(`\\fax(-?\d+(\.\d+)?)`)
-	s = reFax.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reFax.FindStringSubmatch(m)
-		return `\fax` + scaleNumberString(sub[1], ratioX)
+	// \move(x1,y1,x2,y2[,t1,t2]): t1,t2 dipertahankan apa adanya
+	inner = replaceSubmatch(reResMove, inner, func(sub []string) string {
+		return `\move(` + r.scale(sub[1], r.rx) + "," + r.scale(sub[2], r.ry) + "," +
+			r.scale(sub[3], r.rx) + "," + r.scale(sub[4], r.ry) + sub[5] + ")"
 	})
-	reFay := regexp.MustCompile(`\\fay(-?\d+(\.\d+)?)`)
-	s = reFay.ReplaceAllStringFunc(s, func(m string) string {
-		sub := reFay.FindStringSubmatch(m)
-		return `\fay` + scaleNumberString(sub[1], ratioY)
+	inner = replaceParenArgs(reResClip, inner, func(sub []string) string {
+		args := strings.TrimSpace(sub[2])
+		if strings.IndexFunc(args, unicode.IsLetter) < 0 {
+			// clip kotak x1,y1,x2,y2
+			return `\` + sub[1] + "(" + r.scalePath(args) + ")"
+		}
+		// vector clip, opsional diawali parameter skala drawing: (2, m 0 0 l ...).
+		// Angka itu level presisi drawing, bukan koordinat, jadi tidak diskalakan.
+		scalePrefix := ""
+		if p := reResClipScale.FindStringSubmatch(args); p != nil {
+			scalePrefix, args = p[1], p[2]
+		}
+		return `\` + sub[1] + "(" + scalePrefix + r.scalePath(args) + ")"
 	})
-
-	// \fscx \fscy \fsc (scale percent) - scale relative? We'll preserve percentages but do not convert them to pixels.
-	// For safety, we will not change \fscx/\fscy (they are percentages). If you'd like, we could attempt to adjust them.
-
-	// \t(...) nested transforms: apply scaling inside tags portion
-	reT := regexp.MustCompile(`\\t\(([^)]*)\)`)
-	// iterate until no change to handle nested
-	for reT.MatchString(s) {
-		s = reT.ReplaceAllStringFunc(s, func(m string) string {
-			sub := reT.FindStringSubmatch(m)
-			if len(sub) < 2 {
-				return m
-			}
-			inner := sub[1]
-			// inner may be "t1,t2, tags" or just tags. We attempt to find the tags part (starting with \)
-			idx := strings.Index(inner, `\`)
-			if idx >= 0 {
-				prefix := inner[:idx]
-				tags := inner[idx:]
-				return `\t(` + prefix + scaleTags(tags, ratioX, ratioY) + `)`
-			}
-			// else scale anything numeric inside
-			return `\t(` + scaleTags(inner, ratioX, ratioY) + `)`
+	// \fsc<n> mengisi \fscx dan \fscy sekaligus; hanya sumbu x yang ikut koreksi
+	// aspek, jadi bila aspeknya berubah dipecah dulu dan \fscx-nya diskalakan
+	// reResSizeTag di bawah
+	if !almostOne(r.ar) {
+		inner = replaceSubmatch(reResFscTag, inner, func(sub []string) string {
+			return `\fscx` + sub[1] + `\fscy` + sub[1]
 		})
 	}
-
-	// done
-	return s
+	// sudut (\frx, \fry, \frz, \fr) dalam derajat, jadi sengaja tidak ada di
+	// reResSizeTag dan lolos apa adanya
+	inner = replaceSubmatch(reResSizeTag, inner, func(sub []string) string {
+		k := r.rm
+		switch sub[1] {
+		case "xbord", "xshad", "fsp":
+			k = r.rx
+		case "ybord", "yshad", "fs", "pbo":
+			k = r.ry
+		case "fscx":
+			k = r.ar
+		case "be":
+			// \be di VSFilter berupa jumlah pass (int); 0 tetap 0 = efek mati.
+			// Dibulatkan hanya jika memang diskalakan (rm bisa 1 walau rx/ry tidak).
+			if almostOne(k) {
+				return sub[0]
+			}
+			return `\be` + strconv.Itoa(int(math.Round(parseFloatSafe(sub[2], 0)*k)))
+		}
+		// tanda dipertahankan (\shad-2 → \shad-3), nol tetap nol (\bord0)
+		return `\` + sub[1] + r.scale(sub[2], k)
+	})
+	return restorePlaceholders(inner, alphas)
 }
 
-// ---------- Main processing function untuk Resample ASS ----------
-func processASS(path string) (string, error) {
-	raw, err := os.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("gagal membaca file: %w", err)
+// eventText memproses override block dan drawing (\p1 dst.) di kolom Text.
+func (r resampler) eventText(text, fontName string) string {
+	var sb strings.Builder
+	drawing := false // \p1..\pN aktif: teks biasa adalah koordinat drawing
+	parity := 0      // jumlah angka drawing sejauh ini, untuk paritas x/y
+	for text != "" {
+		open := strings.Index(text, "{")
+		end := -1
+		if open >= 0 {
+			end = strings.Index(text[open:], "}")
+		}
+		// { tanpa } sesudahnya di baris ini bukan override (libass/VSFilter juga
+		// menampilkannya apa adanya), jadi sisa teks diperlakukan sebagai teks biasa
+		if open < 0 || end < 0 {
+			open, end = len(text), 0
+		}
+		plain := text[:open]
+		if drawing {
+			plain = r.scalePathAt(plain, &parity)
+		}
+		sb.WriteString(plain)
+		if open == len(text) {
+			break
+		}
+		inner := text[open+1 : open+end]
+		if m := reResDrawLevel.FindAllStringSubmatch(inner, -1); m != nil {
+			drawing = drawLevel(m[len(m)-1][1]) != 0
+			parity = 0
+		}
+		inner = r.overrideBlock(inner)
+		if fontName != "" {
+			inner = reResFontName.ReplaceAllString(inner, `\fn`+fontName)
+		}
+		sb.WriteString("{" + inner + "}")
+		text = text[open+end+1:]
 	}
-	text := string(raw)
-
-	// Normalize line endings to \n
-	text = strings.ReplaceAll(text, "\r\n", "\n")
-	text = strings.ReplaceAll(text, "\r", "\n")
-
-	// 1) Find PlayResX / PlayResY in [Script Info]
-	rePlayResX := regexp.MustCompile(`(?mi)^\s*PlayResX\s*:\s*(\d+)\s*$`)
-	rePlayResY := regexp.MustCompile(`(?mi)^\s*PlayResY\s*:\s*(\d+)\s*$`)
-	origX := defaultPlayResX
-	origY := defaultPlayResY
+	return sb.String()
+}
 
-	if m := rePlayResX.FindStringSubmatch(text); len(m) >= 2 {
-		origX = parseFloatSafe(m[1], defaultPlayResX)
+// ResampleASS menskalakan script ASS dari PlayRes-nya (default 1280x720 jika
+// tidak ada) ke targetW x targetH dan mengembalikan isi ASS baru. Format kolom
+// Style/Dialogue dibaca dari baris Format: tiap section. Di [Script Info] hanya
+// PlayResX/Y yang diubah; baris lain (ScaledBorderAndShadow, YCbCr Matrix, dst.)
+// disalin apa adanya karena mengubah matrix ikut mengubah warna hasil render.
+func ResampleASS(content string, targetW, targetH int, opts ...Option) (string, error) {
+	if targetW <= 0 || targetH <= 0 {
+		return "", fmt.Errorf("resolusi target tidak valid: %dx%d", targetW, targetH)
 	}
-	if m := rePlayResY.FindStringSubmatch(text); len(m) >= 2 {
-		origY = parseFloatSafe(m[1], defaultPlayResY)
+	var cfg resampleConfig
+	for _, o := range opts {
+		o(&cfg)
 	}
-	ratioX := targetPlayResX / origX
-	ratioY := targetPlayResY / origY
 
-	// Replace or insert PlayResX / PlayResY
-	if rePlayResX.MatchString(text) {
-		text = rePlayResX.ReplaceAllString(text, fmt.Sprintf("PlayResX: %d", int(targetPlayResX)))
-	} else {
-		// insert after [Script Info] header if present, otherwise at top
-		reScriptInfo := regexp.MustCompile(`(?m)^\[Script Info\]\s*$`)
-		if loc := reScriptInfo.FindStringIndex(text); loc != nil {
-			insertAt := loc[1]
-			text = text[:insertAt] + "\nPlayResX: 1920\n" + text[insertAt:]
-		} else {
-			text = "[Script Info]\nPlayResX: 1920\n" + text
+	// Normalize line endings to \n
+	content = normalizeEOL(strings.TrimPrefix(content, "\ufeff"))
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("file ASS kosong")
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	// 1) PlayRes sumber
+	srcX, srcY := defaultPlayResX, defaultPlayResY
+	for _, ln := range lines {
+		lower := strings.ToLower(strings.TrimSpace(ln))
+		if strings.HasPrefix(lower, "playresx:") {
+			srcX = parseFloatSafe(lower[len("playresx:"):], defaultPlayResX)
+		} else if strings.HasPrefix(lower, "playresy:") {
+			srcY = parseFloatSafe(lower[len("playresy:"):], defaultPlayResY)
 		}
 	}
-	if rePlayResY.MatchString(text) {
-		text = rePlayResY.ReplaceAllString(text, fmt.Sprintf("PlayResY: %d", int(targetPlayResY)))
-	} else {
-		reScriptInfo := regexp.MustCompile(`(?m)^\[Script Info\]\s*$`)
-		if loc := reScriptInfo.FindStringIndex(text); loc != nil {
-			insertAt := loc[1]
-			text = text[:insertAt] + "\nPlayResY: 1080\n" + text[insertAt:]
-		} else {
-			text = "[Script Info]\nPlayResY: 1080\n" + text
-		}
-	}
-
-	// 2) Process [V4+ Styles] block
-	lower := strings.ToLower(text)
-	header := "[v4+ styles]"
-	hIdx := strings.Index(lower, header)
-	if hIdx != -1 {
-		// find block start and end
-		// get substring from header position
-		sub := text[hIdx:]
-		// find next section header after header
-		reSection := regexp.MustCompile(`(?m)^\[.+\]`)
-		locs := reSection.FindAllStringIndex(sub, -1)
-		endRel := len(sub)
-		if len(locs) >= 2 {
-			// locs[0] == header itself; next is end
-			endRel = locs[1][0]
-		}
-		block := sub[:endRel] // includes header line
-		// process block line by line
-		lines := strings.Split(block, "\n")
-		formatFields := []string{}
-		styleIndices := []int{} // indices in lines where Style: occurs
-		for i, ln := range lines {
-			lt := strings.TrimSpace(ln)
-			lowerln := strings.ToLower(lt)
-			if strings.HasPrefix(lowerln, "format:") {
-				// capture format order
-				fmtLine := strings.TrimSpace(ln[len("format:"):])
-				parts := strings.Split(fmtLine, ",")
-				formatFields = make([]string, 0, len(parts))
-				for _, p := range parts {
-					formatFields = append(formatFields, strings.ToLower(strings.TrimSpace(p)))
+	if srcX <= 0 || srcY <= 0 {
+		return "", fmt.Errorf("PlayRes sumber tidak valid: %vx%v", srcX, srcY)
+	}
+	w, h := float64(targetW), float64(targetH)
+	r := resampler{rx: w / srcX, ry: h / srcY}
+	r.rm = math.Sqrt(r.rx * r.ry)
+	r.ar = (w / h) / (srcX / srcY)
+	verbosef("resample: PlayRes %sx%s → %dx%d, rx=%s ry=%s rm=%s ar=%s",
+		formatScaled(srcX), formatScaled(srcY), targetW, targetH,
+		formatScaled(r.rx), formatScaled(r.ry), formatScaled(r.rm), formatScaled(r.ar))
+	nStyles, nEvents := 0, 0
+
+	// 2) baris per baris dengan pemetaan Format: dinamis
+	var out []string
+	section := ""
+	styleFormat, eventFormat := defaultStyleFormat, defaultEventFormat
+	sawPlayResX, sawPlayResY := false, false
+	extraStyleDone := cfg.extraStyle == ""
+	lastStyle := -1 // indeks di out: tempat extraStyle disisipkan
+
+	// flushSection dipanggil saat keluar dari sebuah section
+	flushSection := func() {
+		switch section {
+		case "script info":
+			// PlayRes yang tidak ada disisipkan di akhir [Script Info]
+			at := len(out)
+			for at > 0 && strings.TrimSpace(out[at-1]) == "" {
+				at--
+			}
+			var add []string
+			if !sawPlayResX {
+				add = append(add, fmt.Sprintf("PlayResX: %d", targetW))
+			}
+			if !sawPlayResY {
+				add = append(add, fmt.Sprintf("PlayResY: %d", targetH))
+			}
+			out = append(out[:at], append(add, out[at:]...)...)
+			sawPlayResX, sawPlayResY = true, true
+		case "v4+ styles", "v4 styles":
+			if !extraStyleDone {
+				at := lastStyle + 1
+				if lastStyle < 0 {
+					at = len(out)
 				}
-			} else if strings.HasPrefix(lowerln, "style:") {
-				styleIndices = append(styleIndices, i)
+				out = append(out[:at], append([]string{cfg.extraStyle}, out[at:]...)...)
+				extraStyleDone = true
 			}
 		}
+	}
 
-		// If formatFields empty, fallback to default ASS order
-		if len(formatFields) == 0 {
-			formatFields = []string{
-				"name", "fontname", "fontsize", "primarycolour", "secondarycolour", "outlinecolour", "backcolour",
-				"bold", "italic", "underline", "strikeout", "scalex", "scaley", "spacing", "angle",
-				"borderstyle", "outline", "shadow", "alignment", "marginl", "marginr", "marginv", "encoding",
-			}
+	for _, ln := range lines {
+		trim := strings.TrimSpace(ln)
+		lower := strings.ToLower(trim)
+
+		// Section yang tidak dikenal ([Aegisub Project Garbage], [Fonts],
+		// [Graphics], ...) disalin apa adanya. Data uuencode di [Fonts]/[Graphics]
+		// boleh berisi "[...]", jadi di sana hanya nama section ASS yang dikenal
+		// yang dianggap header baru.
+		isHeader := strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]")
+		if isHeader && (section == "fonts" || section == "graphics") {
+			isHeader = knownASSSections[lower[1:len(lower)-1]]
+		}
+		if isHeader {
+			flushSection()
+			section = strings.ToLower(trim[1 : len(trim)-1])
+			out = append(out, ln)
+			continue
 		}
 
-		// determine indices
-		fontIdx := -1
-		fsIdx := -1
-		for i, f := range formatFields {
-			if f == "fontname" && fontIdx == -1 {
-				fontIdx = i
+		switch section {
+		case "fonts", "graphics":
+			// blob uuencode: disalin persis, walau kebetulan diawali "Style:" / "Dialogue:"
+			out = append(out, ln)
+			continue
+
+		case "script info":
+			// selain PlayRes, baris Script Info jatuh ke append di bawah tanpa diubah
+			if strings.HasPrefix(lower, "playresx:") {
+				out = append(out, fmt.Sprintf("PlayResX: %d", targetW))
+				sawPlayResX = true
+				continue
 			}
-			if f == "fontsize" && fsIdx == -1 {
-				fsIdx = i
+			if strings.HasPrefix(lower, "playresy:") {
+				out = append(out, fmt.Sprintf("PlayResY: %d", targetH))
+				sawPlayResY = true
+				continue
 			}
-		}
-		// when mapping into parts, note that Style: content fields correspond to formatFields order
-		// process style lines
-		for _, si := range styleIndices {
-			ln := lines[si]
-			// preserve original prefix ("Style:" plus possibly spaces)
-			prefix := ln[:strings.Index(strings.ToLower(ln), "style:")+6] // "Style:" (6 chars)
-			content := strings.TrimSpace(ln[len(prefix):])
-			// split into len(formatFields) parts
-			parts := splitNPreserveTrailing(content, ',', len(formatFields))
-			// ensure parts has length == len(formatFields)
-			if len(parts) < len(formatFields) {
-				// pad
-				for len(parts) < len(formatFields) {
-					parts = append(parts, "")
+
+		case "v4+ styles", "v4 styles":
+			if strings.HasPrefix(lower, "format:") {
+				styleFormat = nil
+				for _, f := range strings.Split(trim[len("format:"):], ",") {
+					styleFormat = append(styleFormat, strings.ToLower(strings.TrimSpace(f)))
 				}
 			}
-			// replace fontname and fontsize if indices valid
-			if fontIdx >= 0 && fontIdx < len(parts) {
-				parts[fontIdx] = targetFontName
-			}
-			if fsIdx >= 0 && fsIdx < len(parts) {
-				oldFs := strings.TrimSpace(parts[fsIdx])
-				if oldFs != "" {
-					if fv, err := strconv.ParseFloat(oldFs, 64); err == nil {
-						newFs := fv * ratioY
-						parts[fsIdx] = scaleFloatFormat(newFs)
-					} else {
-						// if parse fail, leave as-is
-					}
+			if strings.HasPrefix(lower, "style:") {
+				if trim == cfg.extraStyle {
+					extraStyleDone = true
 				}
+				out = append(out, r.styleLine(trim, styleFormat, cfg.fontName))
+				nStyles++
+				lastStyle = len(out) - 1
+				continue
 			}
-			lines[si] = "Style: " + strings.Join(parts, ",")
-		}
 
-		// Insert resStyleLine at the end of style list (i.e., after last Style: line and before next non-style in block)
-		insertAt := -1
-		if len(styleIndices) > 0 {
-			insertAt = styleIndices[len(styleIndices)-1] + 1
-		} else {
-			// if no style lines, try to insert after Format: if exists, else after header line (index 0)
-			foundFmt := false
-			for i, ln := range lines {
-				if strings.HasPrefix(strings.ToLower(strings.TrimSpace(ln)), "format:") {
-					insertAt = i + 1
-					foundFmt = true
-					break
+		case "events":
+			if strings.HasPrefix(lower, "format:") {
+				eventFormat = nil
+				for _, f := range strings.Split(trim[len("format:"):], ",") {
+					eventFormat = append(eventFormat, strings.ToLower(strings.TrimSpace(f)))
 				}
 			}
-			if !foundFmt {
-				insertAt = 1 // after header line
+			// Comment: ikut diskalakan supaya \pos dsb. tetap benar saat di-uncomment
+			if strings.HasPrefix(lower, "dialogue:") || strings.HasPrefix(lower, "comment:") {
+				out = append(out, r.eventLine(trim, eventFormat, cfg.fontName))
+				nEvents++
+				continue
 			}
 		}
-		// insert res style
-		// ensure we do not duplicate if already present
-		already := false
-		for _, ln := range lines {
-			if strings.TrimSpace(ln) == resStyleLine {
-				already = true
-				break
+		out = append(out, ln)
+	}
+	flushSection()
+	if !sawPlayResX || !sawPlayResY {
+		// tidak ada [Script Info] sama sekali
+		out = append([]string{"[Script Info]",
+			fmt.Sprintf("PlayResX: %d", targetW), fmt.Sprintf("PlayResY: %d", targetH), ""}, out...)
+	}
+
+	verbosef("resample: %d style ditulis ulang, %d baris Dialogue/Comment diskalakan", nStyles, nEvents)
+	return strings.Join(out, "\n") + "\n", nil
+}
+
+// styleLine menskalakan satu baris Style: sesuai format kolom.
+func (r resampler) styleLine(line string, format []string, fontName string) string {
+	parts := splitNPreserveTrailing(line[len("style:"):], ',', len(format))
+	for i := 0; i < len(parts) && i < len(format); i++ {
+		if format[i] == "fontname" {
+			if fontName != "" {
+				parts[i] = fontName
 			}
+			continue
 		}
-		if !already {
-			if insertAt < 0 {
-				lines = append(lines, resStyleLine)
-			} else if insertAt >= len(lines) {
-				lines = append(lines, resStyleLine)
-			} else {
-				// insert
-				head := append([]string{}, lines[:insertAt]...)
-				head = append(head, resStyleLine)
-				head = append(head, lines[insertAt:]...)
-				lines = head
-			}
-		}
-
-		// reconstruct block and replace in text
-		newBlock := strings.Join(lines, "\n")
-		text = text[:hIdx] + newBlock + text[hIdx+len(block):]
-	}
-
-	// 3) Process [Events] block: replace \fn only if present in overrides and scale tags inside overrides
-	reEventsHeader := regexp.MustCompile(`(?mi)^\[Events\]\s*$`)
-	loc := reEventsHeader.FindStringIndex(text)
-	if loc != nil {
-		eventsStart := loc[1]
-		// find next section header after eventsStart
-		reSection := regexp.MustCompile(`(?m)^\[.+\]`)
-		rest := text[eventsStart:]
-		nexts := reSection.FindAllStringIndex(rest, -1)
-		eventsBlock := ""
-		eventsEndRel := len(rest)
-		if len(nexts) >= 1 {
-			eventsBlock = rest[:nexts[0][0]]
-			eventsEndRel = nexts[0][0]
-		} else {
-			eventsBlock = rest
-			eventsEndRel = len(rest)
-		}
-		lines := strings.Split(eventsBlock, "\n")
-		// for each Dialogue line process
-		for i, ln := range lines {
-			trim := strings.TrimSpace(ln)
-			if strings.HasPrefix(strings.ToLower(trim), "dialogue:") {
-				// Format: Dialogue: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
-				// We'll split into 9 commas then the rest as text: SplitN with 10 parts
-				parts := splitNPreserveTrailing(ln, ',', 10)
-				if len(parts) < 10 {
-					// fallback: leave unchanged
-					continue
-				}
-				textField := parts[9]
-
-				// find all override blocks { ... } and process each
-				reOverride := regexp.MustCompile(`\{[^}]*\}`)
-				textField = reOverride.ReplaceAllStringFunc(textField, func(ov string) string {
-					inside := ov[1 : len(ov)-1] // without braces
-					// if has \fn, replace it (only if present)
-					reFn := regexp.MustCompile(`\\fn[^\\}]+`)
-					if reFn.MatchString(inside) {
-						inside = reFn.ReplaceAllString(inside, `\fn`+targetFontName)
-					}
-					// scale tags inside override
-					inside = scaleTags(inside, ratioX, ratioY)
-					return "{" + inside + "}"
-				})
+		v, err := strconv.ParseFloat(parts[i], 64)
+		if err != nil || almostOne(r.styleRatio(format[i])) {
+			// rasio 1: nilai asli dipertahankan apa adanya (mis. 20.5, 0010)
+			continue
+		}
+		switch format[i] {
+		case "fontsize":
+			parts[i] = strconv.Itoa(int(v*r.ry + 0.5))
+		case "scalex":
+			parts[i] = formatScaled(v * r.ar)
+		case "spacing":
+			parts[i] = formatScaled(v * r.rx)
+		case "outline", "shadow":
+			parts[i] = formatScaled(v * r.ry)
+		case "marginl", "marginr":
+			parts[i] = strconv.Itoa(int(v*r.rx + 0.5))
+		case "marginv":
+			parts[i] = strconv.Itoa(int(v*r.ry + 0.5))
+		}
+	}
+	return "Style: " + strings.Join(parts, ",")
+}
 
-				// Also, there might be inline \fn outside braces (rare) - but PER REQUEST, only alter if in override. So we won't change outside.
+// styleRatio: rasio yang dipakai styleLine untuk satu kolom Style; 1 untuk
+// kolom yang tidak diskalakan.
+func (r resampler) styleRatio(field string) float64 {
+	switch field {
+	case "fontsize", "outline", "shadow", "marginv":
+		return r.ry
+	case "scalex":
+		return r.ar
+	case "spacing", "marginl", "marginr":
+		return r.rx
+	}
+	return 1
+}
 
-				parts[9] = textField
-				// reconstruct the line using comma as separator (we used split that preserved trailing text)
-				lines[i] = strings.Join(parts, ",")
-			}
+// eventLine menskalakan margin dan teks satu baris Dialogue:/Comment:. Hanya
+// Text yang boleh berisi koma: kolom sebelum Text di-split dari kiri, sesudahnya
+// dari kanan, jadi urutan Format yang tidak standar tetap aman.
+func (r resampler) eventLine(line string, format []string, fontName string) string {
+	colon := strings.Index(line, ":")
+	kind, body := line[:colon], strings.TrimSpace(line[colon+1:])
+	textIdx := -1
+	for i, name := range format {
+		if name == "text" {
+			textIdx = i
+		}
+	}
+	if textIdx < 0 || strings.Count(body, ",") < len(format)-1 {
+		// malformed / tanpa kolom Text, biarkan apa adanya
+		return line
+	}
+	parts := strings.SplitN(body, ",", textIdx+1)
+	tail := strings.Split(parts[textIdx], ",")
+	nAfter := len(format) - textIdx - 1
+	parts[textIdx] = strings.Join(tail[:len(tail)-nAfter], ",")
+	parts = append(parts, tail[len(tail)-nAfter:]...)
+
+	// kolom lain (termasuk Effect: Banner;50;0;0, Scroll up;y1;y2;...) ditulis
+	// ulang apa adanya lewat posisinya di Format, tidak diskalakan
+	for i, name := range format {
+		v, err := strconv.Atoi(strings.TrimSpace(parts[i]))
+		if err != nil {
+			continue
+		}
+		switch {
+		case (name == "marginl" || name == "marginr") && !almostOne(r.rx):
+			parts[i] = strconv.Itoa(int(float64(v)*r.rx + 0.5))
+		case name == "marginv" && !almostOne(r.ry):
+			parts[i] = strconv.Itoa(int(float64(v)*r.ry + 0.5))
 		}
-		// reconstruct eventsBlock
-		newEventsBlock := strings.Join(lines, "\n")
-		// replace in original text
-		prefix := text[:eventsStart]
-		suffix := text[eventsStart+eventsEndRel:]
-		text = prefix + newEventsBlock + suffix
 	}
+	parts[textIdx] = r.eventText(parts[textIdx], fontName)
+	return kind + ": " + strings.Join(parts, ",")
+}
 
-	// ensure trailing newline
-	if !strings.HasSuffix(text, "\n") {
-		text += "\n"
+// ---------- Main processing function untuk Resample ASS ----------
+func processASS(path string) (string, error) {
+	raw, err := readInput(path)
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca file: %w", err)
 	}
+	return resampleLimenime(string(raw))
+}
 
-	return text, nil
+// resampleLimenime me-resample isi ASS ke target limenime (atau limesub.json).
+func resampleLimenime(content string) (string, error) {
+	// resolusi & font bisa diganti lewat limesub.json (sudah divalidasi saat dimuat)
+	w, h, _ := parseResolution(cfg.resolution())
+	return ResampleASS(content, w, h,
+		WithFontName(cfg.fontName()), WithExtraStyle(cfg.style("res", resStyleLine)))
 }
 //===batas resample ass===
 
+// ======================================
+// 🔹 Diagnostik ASS (-check)
+// ======================================
+
+// fsDeviationRatio: \fs di event yang >= rasio ini (atau <= 1/rasio) dari
+// fontsize style-nya dianggap mencurigakan (kemungkinan typo).
+const fsDeviationRatio = 3.0
+
+var reFsOverride = regexp.MustCompile(`\\fs(\d+(?:\.\d+)?)`)
+
+// checkFontSizeOverrides mengembalikan peringatan untuk setiap override \fs
+// yang menyimpang jauh dari fontsize style yang dipakai event tersebut.
+func checkFontSizeOverrides(content string) []string {
+	content = normalizeEOL(content)
+
+	var warnings []string
+	styleSizes := map[string]float64{}
+	var styleFormat, eventFormat []string
+	section := ""
+	indexOf := func(fields []string, name string) int {
+		for i, f := range fields {
+			if f == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for n, ln := range strings.Split(content, "\n") {
+		trim := strings.TrimSpace(ln)
+		lower := strings.ToLower(trim)
+		if strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]") {
+			section = lower
+			continue
+		}
+		if strings.HasPrefix(lower, "format:") {
+			fields := strings.Split(trim[len("format:"):], ",")
+			for i := range fields {
+				fields[i] = strings.ToLower(strings.TrimSpace(fields[i]))
+			}
+			if section == "[events]" {
+				eventFormat = fields
+			} else {
+				styleFormat = fields
+			}
+			continue
+		}
+		if strings.HasPrefix(lower, "style:") && len(styleFormat) > 0 {
+			parts := splitNPreserveTrailing(trim[len("style:"):], ',', len(styleFormat))
+			ni, fi := indexOf(styleFormat, "name"), indexOf(styleFormat, "fontsize")
+			if ni >= 0 && fi >= 0 && ni < len(parts) && fi < len(parts) {
+				styleSizes[parts[ni]] = parseFloatSafe(parts[fi], 0)
+			}
+			continue
+		}
+		if strings.HasPrefix(lower, "dialogue:") && len(eventFormat) > 0 {
+			parts := splitNPreserveTrailing(trim[len("dialogue:"):], ',', len(eventFormat))
+			si, ti := indexOf(eventFormat, "style"), indexOf(eventFormat, "text")
+			if si < 0 || ti < 0 || si >= len(parts) || ti >= len(parts) {
+				continue
+			}
+			style := parts[si]
+			base, ok := styleSizes[style]
+			if !ok || base <= 0 {
+				continue
+			}
+			for _, m := range reFsOverride.FindAllStringSubmatch(parts[ti], -1) {
+				fs := parseFloatSafe(m[1], 0)
+				if fs <= 0 {
+					continue
+				}
+				ratio := fs / base
+				if ratio >= fsDeviationRatio || ratio <= 1/fsDeviationRatio {
+					warnings = append(warnings, fmt.Sprintf(
+						"baris %d: \\fs%s = %.1fx fontsize style \"%s\" (%s)",
+						n+1, m[1], ratio, style, formatScaled(base)))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
 // ======================
 // TTML / Custom XML types
 // ======================
@@ -526,14 +854,20 @@ type TTMLParagraph struct {
 	XMLName xml.Name `xml:"p"`
 	Begin   string   `xml:"begin,attr"`
 	End     string   `xml:"end,attr"`
+	Lang    string   `xml:"lang,attr"` // xml:lang; kosong = ikut div/body/tt
 	Text    string   `xml:",innerxml"`
 }
 
 // 🔹 Struktur baru untuk TTML umum
 type TTMLRoot struct {
-	XMLName xml.Name `xml:"tt"`
-	Body    struct {
-		Div []struct {
+	XMLName             xml.Name `xml:"tt"`
+	FrameRate           string   `xml:"frameRate,attr"`           // ttp:frameRate
+	FrameRateMultiplier string   `xml:"frameRateMultiplier,attr"` // ttp:frameRateMultiplier, mis. "1000 1001"
+	Lang                string   `xml:"lang,attr"`                // xml:lang default dokumen
+	Body                struct {
+		Lang string `xml:"lang,attr"`
+		Div  []struct {
+			Lang       string          `xml:"lang,attr"`
 			Paragraphs []TTMLParagraph `xml:"p"`
 		} `xml:"div"`
 		Paragraphs []TTMLParagraph `xml:"p"` // Untuk struktur tanpa div
@@ -565,9 +899,15 @@ var (
 // ======================================
 // 🔹 Helper: Deep HTML Unescape
 // ======================================
+const maxUnescapePasses = 10
+
+// Referensi angka (&#233; &#x1F600; termasuk yang tanpa titik koma seperti
+// &#160text) sudah ditangani html.UnescapeString.
 func deepUnescapeHTML(s string) string {
+	// escape berlapis wajar paling 2-3 kali (&amp;amp;nbsp;); batas ini mencegah
+	// input aneh/jahat (&amp;amp;amp;...) memaksa satu pass per lapis
 	prev := ""
-	for s != prev {
+	for n := 0; s != prev && n < maxUnescapePasses; n++ {
 		prev = s
 		s = html.UnescapeString(s)
 	}
@@ -586,17 +926,62 @@ func deepUnescapeHTML(s string) string {
 	return s
 }
 
+// unescapeNonXMLEntities: hanya entity HTML bernama yang tidak dikenal parser XML
+// (&nbsp;, &eacute;, ...) yang diganti; &lt; &gt; &amp; &quot; &apos; dibiarkan
+// supaya struktur dokumen tetap utuh. Referensi angka juga di-decode di sini,
+// karena encoding/xml menolak yang tanpa titik koma (&#160text, umum di data
+// hasil scraping) dan yang di luar rentang karakter XML (&#0;).
+var (
+	reNamedEntity   = regexp.MustCompile(`&([A-Za-z][A-Za-z0-9]*);`)
+	reNumericEntity = regexp.MustCompile(`&#([xX][0-9A-Fa-f]+|[0-9]+);?`)
+)
+
+func unescapeNonXMLEntities(s string) string {
+	s = reNumericEntity.ReplaceAllStringFunc(s, func(m string) string {
+		num := strings.TrimSuffix(m[2:], ";")
+		base := 10
+		if num[0] == 'x' || num[0] == 'X' {
+			num, base = num[1:], 16
+		}
+		v, err := strconv.ParseUint(num, base, 32)
+		r := rune(v)
+		switch {
+		case err != nil || v > unicode.MaxRune || (r >= 0xD800 && r <= 0xDFFF) || r == 0:
+			return "\uFFFD"
+		case strings.ContainsRune(`<>&"'`, r):
+			return fmt.Sprintf("&#%d;", v) // karakter struktur XML tetap berupa referensi
+		}
+		return string(r)
+	})
+	return reNamedEntity.ReplaceAllStringFunc(s, func(m string) string {
+		switch m {
+		case "&lt;", "&gt;", "&amp;", "&quot;", "&apos;":
+			return m
+		}
+		if v := deepUnescapeHTML(m); v != m {
+			return v
+		}
+		return m
+	})
+}
+
 // ======================================
 // 🔹 Fungsi: Convert Custom XML → SRT (in-memory)
 // ======================================
 func convertCustomXMLtoSRT(filePath string) (string, error) {
-	data, err := os.ReadFile(filePath)
+	data, err := readInput(filePath)
 	if err != nil {
 		return "", err
 	}
+	return customXMLToSRT(data)
+}
 
-	// Use deep unescape to handle double-escaped and non-standard entities
-	content := deepUnescapeHTML(string(data))
+// customXMLToSRT: isi Custom XML → SRT, tanpa membaca file.
+func customXMLToSRT(data []byte) (string, error) {
+	// Dokumen mentah hanya dibersihkan dari entity non-XML (&nbsp; dll.);
+	// deep unescape dilakukan per teks setelah parse supaya markup yang
+	// di-escape (&lt;p&gt;) tidak merusak struktur XML
+	content := unescapeNonXMLEntities(string(data))
 
 	var xmlRoot CustomXMLRoot
 	if err := xml.Unmarshal([]byte(content), &xmlRoot); err != nil {
@@ -607,10 +992,15 @@ func convertCustomXMLtoSRT(filePath string) (string, error) {
 		return "", fmt.Errorf("tidak ada subtitle ditemukan dalam custom XML")
 	}
 
+	unit := opts.TimeUnit
+	if unit == "" || unit == "auto" {
+		unit = detectCustomXMLTimeUnit(xmlRoot)
+	}
+
 	var sb strings.Builder
 	counter := 1
 
-	for _, dia := range xmlRoot.Dia {
+	for i, dia := range xmlRoot.Dia {
 		// deep unescape also applied to inner text
 		text := deepUnescapeHTML(strings.TrimSpace(dia.Sub))
 		if text == "" {
@@ -618,18 +1008,28 @@ func convertCustomXMLtoSRT(filePath string) (string, error) {
 		}
 
 		// Handle line breaks dalam CDATA
-		text = strings.ReplaceAll(text, "\n", "\\N")
-
-		// Convert waktu dari centiseconds ke format SRT
-		startTime := centisecondsToSRTTime(dia.ST)
-		endTime := centisecondsToSRTTime(dia.ET)
-
-		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
-			counter,
-			startTime,
-			endTime,
-			text))
-		counter++
+		text = strings.ReplaceAll(text, "\n", assLineBreak())
+
+		// posisi <style><position> dibawa lewat SRT sebagai override untuk processSRT
+		pos := dia.Style.Position
+		text = customXMLPositionTags(pos.Alignment, pos.HorizontalMargin, pos.VerticalMargin) + text
+
+		// Convert waktu (centiseconds / ms / detik / jam) ke format SRT
+		var endTime string
+		startTime, err := customXMLTimeToSRT(dia.ST, unit)
+		if err == nil {
+			endTime, err = customXMLTimeToSRT(dia.ET, unit)
+			if err == nil {
+				sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
+					counter,
+					startTime,
+					endTime,
+					text))
+				counter++
+				continue
+			}
+		}
+		fmt.Fprintf(os.Stderr, "peringatan: <dia> ke-%d dilewati: %v\n", i+1, err)
 	}
 
 	if counter == 1 {
@@ -640,41 +1040,134 @@ func convertCustomXMLtoSRT(filePath string) (string, error) {
 }
 
 // ======================================
-// 🔹 Helper: Convert centiseconds to SRT time
+// 🔹 Helper: posisi Custom XML → override
 // ======================================
-func centisecondsToSRTTime(cs string) string {
-	centiseconds, err := strconv.Atoi(cs)
-	if err != nil {
-		return "00:00:00,000"
-	}
-
-	// Convert centiseconds to milliseconds
-	milliseconds := centiseconds * 10
 
-	hours := milliseconds / 3600000
-	milliseconds %= 3600000
-
-	minutes := milliseconds / 60000
-	milliseconds %= 60000
+// customXMLPositionTags: alignment → {\anN}; margin → {\margin(L,R,V)}, tag internal
+// yang dibaca processSRT menjadi kolom margin per Dialogue. Margin boleh piksel
+// (resolusi target) atau persen ("5%").
+func customXMLPositionTags(alignment, hMargin, vMargin string) string {
+	var tags string
+	if an := customXMLAlignment(alignment); an != 0 && an != 2 {
+		tags += fmt.Sprintf(`{\an%d}`, an)
+	}
+	h := customXMLMargin(hMargin, targetPlayResX)
+	v := customXMLMargin(vMargin, targetPlayResY)
+	if h > 0 || v > 0 {
+		tags += fmt.Sprintf(`{\margin(%d,%d,%d)}`, h, h, v)
+	}
+	return tags
+}
 
-	seconds := milliseconds / 1000
-	milliseconds %= 1000
+// customXMLAlignment: "top-right", "bottom_center", "middle left", atau angka numpad 1-9.
+func customXMLAlignment(s string) int {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= 1 && n <= 9 {
+			return n
+		}
+		return 0
+	}
+	row, col := 0, 2 // row: 0 bawah, 3 tengah, 6 atas
+	words := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		switch w {
+		case "top":
+			row = 6
+		case "middle":
+			row = 3
+		case "bottom":
+			row = 0
+		case "left", "start":
+			col = 1
+		case "right", "end":
+			col = 3
+		}
+	}
+	return row + col
+}
 
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, milliseconds)
+func customXMLMargin(s string, full float64) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	if strings.HasSuffix(s, "%") {
+		return int(parseFloatSafe(strings.TrimSuffix(s, "%"), 0)/100*full + 0.5)
+	}
+	return int(parseFloatSafe(strings.TrimSuffix(s, "px"), 0) + 0.5)
 }
 
 // ======================================
-// 🔹 Fungsi: Convert VTT → SRT (in-memory)
+// 🔹 Helper: waktu Custom XML → SRT time
 // ======================================
-func convertVTTtoSRT(filePath string) (string, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return "", err
-	}
 
-	// deep unescape for VTT content too
-	content := deepUnescapeHTML(string(data))
-	lines := strings.Split(content, "\n")
+// detectCustomXMLTimeUnit menebak satuan angka <st>/<et> untuk seluruh file:
+// durasi median di atas 1000 hampir pasti milidetik (1000 cs = 10 detik per
+// baris jarang terjadi), selain itu centiseconds seperti format aslinya.
+func detectCustomXMLTimeUnit(root CustomXMLRoot) string {
+	var durs []int
+	for _, dia := range root.Dia {
+		st, err1 := strconv.Atoi(strings.TrimSpace(dia.ST))
+		et, err2 := strconv.Atoi(strings.TrimSpace(dia.ET))
+		if err1 == nil && err2 == nil && et > st {
+			durs = append(durs, et-st)
+		}
+	}
+	if len(durs) == 0 {
+		return "cs"
+	}
+	sort.Ints(durs)
+	if durs[len(durs)/2] > 1000 {
+		return "ms"
+	}
+	return "cs"
+}
+
+// customXMLTimeToSRT: nilai berisi ":" dibaca sebagai jam (HH:MM:SS.mmm),
+// angka dibaca sesuai unit ("cs", "ms", atau "s").
+func customXMLTimeToSRT(v, unit string) (string, error) {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, ":") {
+		sec, ok := parseGenericXMLTime(v)
+		if !ok {
+			return "", fmt.Errorf("waktu %q tidak valid", v)
+		}
+		return formatTime(sec), nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("waktu %q tidak valid", v)
+	}
+	switch unit {
+	case "ms":
+		return formatTime(n / 1000), nil
+	case "s":
+		return formatTime(n), nil
+	default:
+		return formatTime(n / 100), nil
+	}
+}
+
+// ======================================
+// 🔹 Fungsi: Convert VTT → SRT (in-memory)
+// ======================================
+func convertVTTtoSRT(filePath string) (string, error) {
+	data, err := readInput(filePath)
+	if err != nil {
+		return "", err
+	}
+	return vttToSRT(data)
+}
+
+// vttToSRT: isi WebVTT → SRT, tanpa membaca file.
+func vttToSRT(data []byte) (string, error) {
+	// deep unescape for VTT content too
+	content := deepUnescapeHTML(normalizeEOL(string(data)))
+	lines := strings.Split(content, "\n")
 
 	var sb strings.Builder
 	counter := 1
@@ -684,16 +1177,25 @@ func convertVTTtoSRT(filePath string) (string, error) {
 	for i < len(lines) {
 		line := strings.TrimSpace(lines[i])
 		if strings.HasPrefix(line, "WEBVTT") {
+			kind := vttKindFromHeader(line)
 			i++
 			// Skip metadata lines setelah WEBVTT
 			for i < len(lines) && strings.Contains(lines[i], ":") {
+				if k, v, ok := strings.Cut(lines[i], ":"); ok && strings.EqualFold(strings.TrimSpace(k), "kind") {
+					kind = strings.ToLower(strings.TrimSpace(v))
+				}
 				i++
 			}
+			if kind == "chapters" || kind == "metadata" || kind == "descriptions" {
+				return "", fmt.Errorf("track VTT tampaknya %s, bukan subtitle", kind)
+			}
 			break
 		}
 		i++
 	}
 
+	sheet := vttStyleSheet{RegionColor: map[string]string{}}
+
 	for i < len(lines) {
 		line := strings.TrimSpace(lines[i])
 		if line == "" {
@@ -701,14 +1203,39 @@ func convertVTTtoSRT(filePath string) (string, error) {
 			continue
 		}
 
-		// Skip cue identifiers (biasanya angka atau teks di atas timing)
-		if !strings.Contains(line, "-->") && i+1 < len(lines) && strings.Contains(lines[i+1], "-->") {
-			i++ // Skip identifier line
+		// Blok komentar NOTE (bisa multi-baris) dilewati sampai baris kosong
+		if line == "NOTE" || strings.HasPrefix(line, "NOTE ") || strings.HasPrefix(line, "NOTE\t") {
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				i++
+			}
+			continue
+		}
+
+		// Blok STYLE / REGION berakhir di baris kosong dan tidak boleh ikut jadi teks
+		if line == "STYLE" || line == "REGION" {
+			var block []string
+			i++
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				block = append(block, lines[i])
+				i++
+			}
+			if line == "STYLE" {
+				sheet.parse(strings.Join(block, "\n"))
+			}
 			continue
 		}
 
+		// Skip cue identifiers (angka atau teks bebas, boleh multi-kata):
+		// hanya dianggap identifier jika baris berikutnya adalah timing
+		if !isVTTTimingLine(line) {
+			if i+1 < len(lines) && isVTTTimingLine(strings.TrimSpace(lines[i+1])) {
+				i++ // Skip identifier line
+				continue
+			}
+		}
+
 		// Cek jika line mengandung timing (-->)
-		if strings.Contains(line, "-->") {
+		if isVTTTimingLine(line) {
 			// Parse timing line
 			timingParts := strings.Split(line, " --> ")
 			if len(timingParts) != 2 {
@@ -718,6 +1245,19 @@ func convertVTTtoSRT(filePath string) (string, error) {
 
 			startTime := vttTimeToSRT(timingParts[0])
 			endTime := vttTimeToSRT(timingParts[1])
+			// cue settings (line:, position:, align:) ada setelah waktu akhir
+			posTag := ""
+			color := sheet.DefaultColor
+			if fields := strings.Fields(timingParts[1]); len(fields) > 1 {
+				posTag = vttCueSettingsToASS(fields[1:])
+				for _, st := range fields[1:] {
+					if region, ok := strings.CutPrefix(st, "region:"); ok {
+						if c, ok := sheet.RegionColor[region]; ok {
+							color = c
+						}
+					}
+				}
+			}
 
 			i++
 			var textLines []string
@@ -725,16 +1265,22 @@ func convertVTTtoSRT(filePath string) (string, error) {
 			// Kumpulkan teks subtitle
 			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
 				// apply deep unescape to each subtitle text line
-				text := deepUnescapeHTML(strings.TrimSpace(lines[i]))
+				// trim ASCII saja: U+00A0 (dari &nbsp;) di awal baris disengaja
+				text := deepUnescapeHTML(strings.Trim(lines[i], " \t\r"))
 				// Handle VTT tags
 				text = vttTagsToSRT(text)
 				if text != "" {
+					// processSRT memproses per baris, jadi warna dibungkus per baris
+					if color != "" {
+						text = `<font color="` + color + `">` + text + `</font>`
+					}
 					textLines = append(textLines, text)
 				}
 				i++
 			}
 
 			if len(textLines) > 0 {
+				textLines[0] = posTag + textLines[0]
 				fullText := strings.Join(textLines, "\n")
 				sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
 					counter, startTime, endTime, fullText))
@@ -752,9 +1298,82 @@ func convertVTTtoSRT(filePath string) (string, error) {
 	return sb.String(), nil
 }
 
+var reVTTTimestamp = regexp.MustCompile(`^(\d+:)?\d+:\d+\.\d+$`)
+
+// isVTTTimingLine: "00:01.000 --> 00:02.000 [settings]" dengan timestamp valid di kedua sisi
+func isVTTTimingLine(line string) bool {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	end := strings.Fields(parts[1])
+	return len(end) > 0 && reVTTTimestamp.MatchString(strings.TrimSpace(parts[0])) && reVTTTimestamp.MatchString(end[0])
+}
+
+// vttKindFromHeader: "WEBVTT - Chapters" / "WEBVTT metadata" → jenis track dari
+// kata pertama sufiks header. Hanya kata pertama yang dicocokkan (persis), karena
+// sisa header teks bebas: "WEBVTT - no chapters metadata here" tetap subtitle.
+func vttKindFromHeader(header string) string {
+	suffix := strings.TrimLeft(strings.TrimPrefix(header, "WEBVTT"), " \t-–—:")
+	first, _, _ := strings.Cut(suffix, " ")
+	switch kind := strings.ToLower(strings.TrimSpace(first)); kind {
+	case "chapters", "metadata", "descriptions":
+		return kind
+	}
+	return ""
+}
+
+// ======================================
+// 🔹 Helper: blok STYLE VTT
+// ======================================
+
+// vttStyleSheet menyimpan warna dari blok STYLE:
+// "::cue { color: ... }" untuk semua cue, "::cue-region(#id) { color: ... }"
+// untuk cue yang memakai region:id.
+type vttStyleSheet struct {
+	DefaultColor string
+	RegionColor  map[string]string
+}
+
+var (
+	reVTTCueRule = regexp.MustCompile(`::cue(-region)?(?:\(\s*#?([^)\s]*)\s*\))?\s*\{([^}]*)\}`)
+	reCSSColor   = regexp.MustCompile(`(?i)(?:^|[;\s])color\s*:\s*([^;]+)`)
+	reCSSRGB     = regexp.MustCompile(`(?i)^rgba?\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)`)
+)
+
+func (sh *vttStyleSheet) parse(css string) {
+	for _, m := range reVTTCueRule.FindAllStringSubmatch(css, -1) {
+		cm := reCSSColor.FindStringSubmatch(m[3])
+		if len(cm) < 2 {
+			continue
+		}
+		color := cssColorToHex(cm[1])
+		switch {
+		case m[1] == "-region" && m[2] != "":
+			sh.RegionColor[m[2]] = color
+		case m[1] == "" && m[2] == "":
+			sh.DefaultColor = color
+		}
+	}
+}
+
+// cssColorToHex: rgb(r,g,b) -> #rrggbb; nilai lain (hex / nama warna) apa adanya.
+func cssColorToHex(v string) string {
+	v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "!important"))
+	if m := reCSSRGB.FindStringSubmatch(v); len(m) == 4 {
+		r, _ := strconv.Atoi(m[1])
+		g, _ := strconv.Atoi(m[2])
+		b, _ := strconv.Atoi(m[3])
+		return fmt.Sprintf("#%02x%02x%02x", r&0xff, g&0xff, b&0xff)
+	}
+	return strings.ToLower(v)
+}
+
 // ======================================
 // 🔹 Helper: VTT time → SRT time
 // ======================================
+var reVTTShortTime = regexp.MustCompile(`(\d+):(\d+)\.(\d+)`) // MM:SS.ms
+
 func vttTimeToSRT(t string) string {
 	// Format VTT: HH:MM:SS.ms atau MM:SS.ms
 	t = strings.TrimSpace(t)
@@ -775,8 +1394,7 @@ func vttTimeToSRT(t string) string {
 	}
 
 	// Coba format tanpa hours: MM:SS.ms
-	reShortTime := regexp.MustCompile(`(\d+):(\d+)\.(\d+)`)
-	if matches := reShortTime.FindStringSubmatch(t); len(matches) >= 4 {
+	if matches := reVTTShortTime.FindStringSubmatch(t); len(matches) >= 4 {
 		min, _ := strconv.Atoi(matches[1])
 		sec, _ := strconv.Atoi(matches[2])
 		ms, _ := strconv.Atoi(matches[3])
@@ -797,26 +1415,107 @@ func vttTimeToSRT(t string) string {
 	return "00:00:00,000"
 }
 
+// ======================================
+// 🔹 Helper: VTT cue settings → override ASS
+// ======================================
+
+// vttCueSettingsToASS menerjemahkan cue settings VTT (line:, position:, align:)
+// menjadi tag {\anN} dan, jika posisi horizontal diketahui, \pos perkiraan
+// pada resolusi target. Mengembalikan "" untuk posisi default (bawah-tengah).
+func vttCueSettingsToASS(settings []string) string {
+	var line, position, align string
+	for _, st := range settings {
+		kv := strings.SplitN(st, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		// "90%,end" -> ambil nilainya saja, abaikan line/position alignment
+		val := strings.SplitN(kv[1], ",", 2)[0]
+		switch strings.ToLower(kv[0]) {
+		case "line":
+			line = val
+		case "position":
+			position = val
+		case "align":
+			align = strings.ToLower(val)
+		}
+	}
+
+	// row: 0 = bawah (\an1-3), 3 = tengah (\an4-6), 6 = atas (\an7-9)
+	row := 0
+	linePct := -1.0
+	if line != "" {
+		if strings.HasSuffix(line, "%") {
+			linePct = parseFloatSafe(strings.TrimSuffix(line, "%"), -1)
+			switch {
+			case linePct < 0:
+			case linePct < 40:
+				row = 6
+			case linePct <= 60:
+				row = 3
+			}
+		} else if n, err := strconv.Atoi(line); err == nil && n >= 0 {
+			// nomor baris positif dihitung dari atas layar
+			row = 6
+		}
+	}
+
+	col := 2
+	switch align {
+	case "start", "left":
+		col = 1
+	case "end", "right":
+		col = 3
+	}
+	an := row + col
+
+	pos := ""
+	if strings.HasSuffix(position, "%") {
+		if xPct := parseFloatSafe(strings.TrimSuffix(position, "%"), -1); xPct >= 0 {
+			yPct := linePct
+			if yPct < 0 {
+				yPct = map[int]float64{0: 90, 3: 50, 6: 10}[row]
+			}
+			pos = fmt.Sprintf("\\pos(%s,%s)",
+				formatScaled(xPct*targetPlayResX/100),
+				formatScaled(yPct*targetPlayResY/100))
+		}
+	}
+
+	if an == 2 && pos == "" {
+		return ""
+	}
+	return fmt.Sprintf("{\\an%d%s}", an, pos)
+}
+
 // ======================================
 // 🔹 Helper: Convert VTT tags to SRT compatible
 // ======================================
+var (
+	reVTTTimestampTag = regexp.MustCompile(`<(\d{2}:\d{2}:\d{2}\.\d{3})>`)
+	reVTTVoice        = regexp.MustCompile(`<v\s+([^>]+)>`)
+	reVTTRuby         = regexp.MustCompile(`<ruby>([^<]*)<rt>([^<]*)</rt></ruby>`)
+	reVTTColorClass   = regexp.MustCompile(`<c\.(#[0-9A-Fa-f]{6})>`)
+	reVTTClass        = regexp.MustCompile(`<c\.[^>]*>`)
+)
+
 func vttTagsToSRT(text string) string {
 	// Convert VTT cue tags to HTML-like tags untuk kompatibilitas
-	text = regexp.MustCompile(`<(\d{2}:\d{2}:\d{2}\.\d{3})>`).ReplaceAllString(text, "") // Remove timestamp tags
+	text = reVTTTimestampTag.ReplaceAllString(text, "") // Remove timestamp tags
 
 	// Convert voice tags <v Speaker> menjadi "Speaker: "
-	text = regexp.MustCompile(`<v\s+([^>]+)>`).ReplaceAllString(text, "$1: ")
+	text = reVTTVoice.ReplaceAllString(text, "$1: ")
 	text = strings.ReplaceAll(text, "</v>", "")
 
 	// Convert Ruby tags (umum di VTT)
-	text = regexp.MustCompile(`<ruby>([^<]*)<rt>([^<]*)</rt></ruby>`).ReplaceAllString(text, "$1")
+	text = reVTTRuby.ReplaceAllString(text, "$1")
 
 	// Convert color tags: <c.color> -> <font color="color">
-	text = regexp.MustCompile(`<c\.(#[0-9A-Fa-f]{6})>`).ReplaceAllString(text, `<font color="$1">`)
+	text = reVTTColorClass.ReplaceAllString(text, `<font color="$1">`)
 	text = strings.ReplaceAll(text, "</c>", "</font>")
 
 	// Convert class tags: <c.class> -> simple text (remove tags)
-	text = regexp.MustCompile(`<c\.[^>]*>`).ReplaceAllString(text, "")
+	text = reVTTClass.ReplaceAllString(text, "")
 	text = strings.ReplaceAll(text, "</c>", "")
 
 	// Bold, Italic, Underline - VTT menggunakan sama seperti HTML
@@ -834,27 +1533,50 @@ func vttTagsToSRT(text string) string {
 // 🔹 Fungsi: Convert TTML → SRT (in-memory, versi kuat)
 // ======================================
 func convertTTMLtoSRT(filePath string) (string, error) {
-	data, err := os.ReadFile(filePath)
+	data, err := readInput(filePath)
 	if err != nil {
 		return "", err
 	}
+	return ttmlToSRT(data)
+}
 
-	// Deep unescape
-	content := deepUnescapeHTML(string(data))
+// ttmlToSRT: isi TTML → SRT, tanpa membaca file.
+func ttmlToSRT(data []byte) (string, error) {
+	// Hanya entity non-XML; teks tiap <p> di-unescape di buildSRTFromParagraphs
+	content := unescapeNonXMLEntities(string(data))
 
 	// 🔹 PARSING TTML UMUM - Coba struktur TTML standar dulu
 	var ttmlRoot TTMLRoot
-	if err := xml.Unmarshal([]byte(content), &ttmlRoot); err == nil {
+	err := xml.Unmarshal([]byte(content), &ttmlRoot)
+	fps := resolveFPS(ttmlFrameRate(ttmlRoot.FrameRate, ttmlRoot.FrameRateMultiplier))
+	if err == nil {
 		var paragraphs []TTMLParagraph
 
-		// Kumpulkan semua paragraf dari berbagai struktur
+		// Kumpulkan semua paragraf dari berbagai struktur; xml:lang diwariskan
+		// tt → body → div → p supaya tiap paragraf tahu bahasanya
+		or := func(lang, parent string) string {
+			if lang == "" {
+				return parent
+			}
+			return lang
+		}
+		inherit := func(ps []TTMLParagraph, lang string) {
+			for _, p := range ps {
+				p.Lang = or(p.Lang, lang)
+				paragraphs = append(paragraphs, p)
+			}
+		}
+		bodyLang := or(ttmlRoot.Body.Lang, ttmlRoot.Lang)
 		for _, div := range ttmlRoot.Body.Div {
-			paragraphs = append(paragraphs, div.Paragraphs...)
+			inherit(div.Paragraphs, or(div.Lang, bodyLang))
 		}
-		paragraphs = append(paragraphs, ttmlRoot.Body.Paragraphs...)
+		inherit(ttmlRoot.Body.Paragraphs, bodyLang)
 
 		if len(paragraphs) > 0 {
-			return buildSRTFromParagraphs(paragraphs)
+			if paragraphs, err = filterTTMLLang(paragraphs, opts.Lang); err != nil {
+				return "", err
+			}
+			return buildSRTFromParagraphs(paragraphs, fps)
 		}
 	}
 
@@ -863,7 +1585,7 @@ func convertTTMLtoSRT(filePath string) (string, error) {
 		Paragraphs []TTMLParagraph `xml:"body>div>p"`
 	}
 	if err := xml.Unmarshal([]byte(content), &root); err == nil && len(root.Paragraphs) > 0 {
-		return buildSRTFromParagraphs(root.Paragraphs)
+		return buildSRTFromParagraphs(root.Paragraphs, fps)
 	}
 
 	// 🔹 FALLBACK 2: struktur <body><p>
@@ -871,7 +1593,7 @@ func convertTTMLtoSRT(filePath string) (string, error) {
 		Paragraphs []TTMLParagraph `xml:"body>p"`
 	}
 	if err := xml.Unmarshal([]byte(content), &alt); err == nil && len(alt.Paragraphs) > 0 {
-		return buildSRTFromParagraphs(alt.Paragraphs)
+		return buildSRTFromParagraphs(alt.Paragraphs, fps)
 	}
 
 	// 🔹 FALLBACK 3: Cari semua tag <p> di mana saja dalam dokumen
@@ -879,36 +1601,227 @@ func convertTTMLtoSRT(filePath string) (string, error) {
 		Paragraphs []TTMLParagraph `xml:"p"`
 	}
 	if err := xml.Unmarshal([]byte(content), &allParagraphs); err == nil && len(allParagraphs.Paragraphs) > 0 {
-		return buildSRTFromParagraphs(allParagraphs.Paragraphs)
+		return buildSRTFromParagraphs(allParagraphs.Paragraphs, fps)
 	}
 
 	return "", fmt.Errorf("gagal parse TTML: tidak ditemukan struktur yang dikenali")
 }
 
+// ttmlLangError: -lang tidak ditemukan. Dibedakan dari error parse supaya
+// srtFromData tidak jatuh ke XML generik yang menggabung semua bahasa.
+type ttmlLangError struct {
+	want  string
+	langs []string
+}
+
+func (e ttmlLangError) Error() string {
+	return fmt.Sprintf("bahasa %q tidak ada di TTML (tersedia: %s)", e.want, strings.Join(e.langs, ", "))
+}
+
+// filterTTMLLang menyaring paragraf TTML multibahasa. Dengan want kosong dan
+// lebih dari satu bahasa, bahasa pertama yang dipakai (dengan peringatan).
+// "en" juga cocok dengan "en-US". Paragraf tanpa xml:lang selalu ikut.
+func filterTTMLLang(paragraphs []TTMLParagraph, want string) ([]TTMLParagraph, error) {
+	var langs []string
+	seen := map[string]bool{}
+	for _, p := range paragraphs {
+		if l := strings.ToLower(p.Lang); l != "" && !seen[l] {
+			seen[l] = true
+			langs = append(langs, p.Lang)
+		}
+	}
+	if want == "" {
+		if len(langs) < 2 {
+			return paragraphs, nil
+		}
+		want = langs[0]
+		fmt.Fprintf(os.Stderr, "peringatan: TTML berisi %d bahasa (%s), hanya %s yang diambil; pilih dengan -lang\n",
+			len(langs), strings.Join(langs, ", "), want)
+	}
+	match := func(lang string) bool {
+		return lang == "" || strings.EqualFold(lang, want) ||
+			len(lang) > len(want) && strings.EqualFold(lang[:len(want)], want) && lang[len(want)] == '-'
+	}
+	var out []TTMLParagraph
+	found := false
+	for _, p := range paragraphs {
+		if match(p.Lang) {
+			found = found || p.Lang != ""
+			out = append(out, p)
+		}
+	}
+	if !found && len(langs) > 0 {
+		return nil, ttmlLangError{want, langs}
+	}
+	verbosef("convert: TTML bahasa %s, %d dari %d paragraf", want, len(out), len(paragraphs))
+	return out, nil
+}
+
+// ======================================
+// 🔹 Fungsi: Convert XML generik → SRT (fallback terakhir)
+// ======================================
+
+// Nama atribut waktu yang dikenali (huruf kecil, tanpa namespace).
+var (
+	genericXMLStartAttrs = map[string]bool{"start": true, "begin": true, "from": true, "starttime": true, "start_time": true, "in": true}
+	genericXMLEndAttrs   = map[string]bool{"end": true, "stop": true, "to": true, "endtime": true, "end_time": true, "out": true}
+	genericXMLDurAttrs   = map[string]bool{"dur": true, "duration": true}
+	reGenericClock       = regexp.MustCompile(`^(?:(\d+):)?(\d+):(\d+(?:[.,]\d+)?)$`)
+	reGenericOffset      = regexp.MustCompile(`^(\d+(?:\.\d+)?)(ms|s)?$`)
+)
+
+// genericXMLOpenCue: elemen bertiming yang sedang dibaca teksnya.
+type genericXMLOpenCue struct {
+	start, end float64
+	text       strings.Builder
+}
+
+// convertGenericXMLtoSRT berjalan di atas xml.Decoder (streaming) dan mengambil
+// setiap elemen yang punya atribut mulai + akhir/durasi dan berisi teks, mis.
+// <subtitle start="1.5" end="3">...</subtitle>. Hanya dipakai setelah parser
+// custom XML dan TTML gagal.
+func convertGenericXMLtoSRT(filePath string) (string, error) {
+	f, err := openInput(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return genericXMLToSRT(f)
+}
+
+// genericXMLToSRT: seperti convertGenericXMLtoSRT, membaca dari r mana pun
+// (mis. bytes.NewReader untuk isi yang sudah ada di memori).
+func genericXMLToSRT(r io.Reader) (string, error) {
+	type cue struct {
+		start, end float64
+		text       string
+	}
+	var stack []*genericXMLOpenCue // nil untuk elemen biasa, supaya EndElement tetap seimbang
+	var cues []cue
+
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if strings.EqualFold(el.Name.Local, "br") {
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] != nil {
+						stack[i].text.WriteString("\n")
+						break
+					}
+				}
+			}
+			stack = append(stack, genericXMLCue(el.Attr))
+		case xml.CharData:
+			// teks masuk ke cue terdalam yang sedang terbuka
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != nil {
+					stack[i].text.Write(el)
+					break
+				}
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top == nil {
+				continue
+			}
+			var lines []string
+			for _, l := range strings.Split(top.text.String(), "\n") {
+				if l = strings.Join(strings.Fields(l), " "); l != "" {
+					lines = append(lines, l)
+				}
+			}
+			if len(lines) > 0 {
+				cues = append(cues, cue{start: top.start, end: top.end, text: strings.Join(lines, "\n")})
+			}
+		}
+	}
+
+	if len(cues) == 0 {
+		return "", fmt.Errorf("tidak ada elemen bertiming yang ditemukan dalam XML")
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].start < cues[j].start })
+
+	var sb strings.Builder
+	for i, c := range cues {
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, formatTime(c.start), formatTime(c.end), c.text))
+	}
+	return sb.String(), nil
+}
+
+// genericXMLCue: cue terbuka jika atribut elemen berisi waktu mulai dan akhir
+// (atau durasi) yang valid, selain itu nil.
+func genericXMLCue(attrs []xml.Attr) *genericXMLOpenCue {
+	start, end, dur := -1.0, -1.0, -1.0
+	for _, a := range attrs {
+		name := strings.ToLower(a.Name.Local)
+		v, ok := parseGenericXMLTime(a.Value)
+		if !ok {
+			continue
+		}
+		switch {
+		case genericXMLStartAttrs[name]:
+			start = v
+		case genericXMLEndAttrs[name]:
+			end = v
+		case genericXMLDurAttrs[name]:
+			dur = v
+		}
+	}
+	if end < 0 && dur >= 0 && start >= 0 {
+		end = start + dur
+	}
+	if start < 0 || end <= start {
+		return nil
+	}
+	return &genericXMLOpenCue{start: start, end: end}
+}
+
+// parseGenericXMLTime: "HH:MM:SS.mmm", "MM:SS,mmm", "12.5", "12.5s", "1500ms" → detik.
+func parseGenericXMLTime(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if m := reGenericClock.FindStringSubmatch(v); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		sec, _ := strconv.ParseFloat(strings.Replace(m[3], ",", ".", 1), 64)
+		return float64(h*3600+min*60) + sec, true
+	}
+	if m := reGenericOffset.FindStringSubmatch(v); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		if m[2] == "ms" {
+			n /= 1000
+		}
+		return n, true
+	}
+	return 0, false
+}
+
 // ======================================
 // 🔹 Helper: Build SRT dari paragraphs
 // ======================================
-func buildSRTFromParagraphs(paragraphs []TTMLParagraph) (string, error) {
+func buildSRTFromParagraphs(paragraphs []TTMLParagraph, fps float64) (string, error) {
 	var sb strings.Builder
 	counter := 1
 
 	for _, p := range paragraphs {
-		text := p.Text
-		text = strings.ReplaceAll(text, "<br/>", "\n")
-		text = strings.ReplaceAll(text, "<br />", "\n")
-		text = strings.ReplaceAll(text, "<br>", "\n")
-		// apply deep unescape to paragraph text (handles CDATA / nested entities)
-		text = deepUnescapeHTML(text)
-		text = stripHTMLTags(text)
-		text = strings.TrimSpace(text)
+		text := strings.TrimSpace(ttmlParagraphText(p.Text))
 
 		if text == "" {
 			continue
 		}
 
 		// Pastikan waktu valid
-		startTime := ttmlTimeToSRT(p.Begin)
-		endTime := ttmlTimeToSRT(p.End)
+		startTime := ttmlTimeToSRT(p.Begin, fps)
+		endTime := ttmlTimeToSRT(p.End, fps)
 
 		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
 			counter,
@@ -926,256 +1839,422 @@ func buildSRTFromParagraphs(paragraphs []TTMLParagraph) (string, error) {
 }
 
 // ======================================
-// 🔹 Helper: TTML time → SRT time (DIPERBAIKI)
+// 🔹 Helper: .sub (MicroDVD / SubViewer 2.0)
 // ======================================
-func ttmlTimeToSRT(t string) string {
-	// Coba format dengan milliseconds dulu: HH:MM:SS.ms
-	if matches := reTimeFull.FindStringSubmatch(t); len(matches) >= 5 {
-		h, _ := strconv.Atoi(matches[1])
-		min, _ := strconv.Atoi(matches[2])
-		sec, _ := strconv.Atoi(matches[3])
-		ms, _ := strconv.Atoi(matches[4])
-		return fmt.Sprintf("%02d:%02d:%02d,%03d", h, min, sec, ms)
-	}
+// Ekstensi .sub dipakai dua format yang tidak berhubungan, jadi dialeknya
+// dibedakan dari isi: baris {frame}{frame} = MicroDVD, header [INFORMATION]
+// atau baris HH:MM:SS.hh,HH:MM:SS.hh = SubViewer 2.0.
 
-	// Coba format tanpa milliseconds: HH:MM:SS
-	if matches := reTimeNoMS.FindStringSubmatch(t); len(matches) >= 4 {
-		h, _ := strconv.Atoi(matches[1])
-		min, _ := strconv.Atoi(matches[2])
-		sec, _ := strconv.Atoi(matches[3])
-		return fmt.Sprintf("%02d:%02d:%02d,000", h, min, sec)
-	}
+var (
+	reMicroDVDLine   = regexp.MustCompile(`^\{(\d+)\}\{(\d*)\}(.*)$`)
+	reMicroDVDCode   = regexp.MustCompile(`\{([a-zA-Z]):([^}]*)\}`)
+	reSubViewerTime  = regexp.MustCompile(`^(\d+):(\d+):(\d+)\.(\d+)\s*,\s*(\d+):(\d+):(\d+)\.(\d+)\s*$`)
+	reSubViewerBreak = regexp.MustCompile(`(?i)\[br\]`)
+)
 
-	// Coba format frames (00:00:00:00)
-	reFrames := regexp.MustCompile(`(\d+):(\d+):(\d+):(\d+)`)
-	if matches := reFrames.FindStringSubmatch(t); len(matches) >= 5 {
-		h, _ := strconv.Atoi(matches[1])
-		min, _ := strconv.Atoi(matches[2])
-		sec, _ := strconv.Atoi(matches[3])
-		frames, _ := strconv.Atoi(matches[4])
-		// Asumsi 25 fps untuk konversi frame ke ms
-		ms := frames * 40
-		return fmt.Sprintf("%02d:%02d:%02d,%03d", h, min, sec, ms)
+// detectSubDialect: "microdvd", "subviewer", atau "" jika tidak dikenali.
+func detectSubDialect(data string) string {
+	for _, ln := range strings.Split(data, "\n") {
+		ln = strings.TrimSpace(strings.TrimPrefix(ln, "\ufeff"))
+		switch {
+		case ln == "":
+			continue
+		case reMicroDVDLine.MatchString(ln):
+			return "microdvd"
+		case strings.EqualFold(ln, "[INFORMATION]"), reSubViewerTime.MatchString(ln):
+			return "subviewer"
+		}
+		return ""
 	}
+	return ""
+}
 
-	// Coba format timecode dengan hours pendek (H:MM:SS.ms)
-	reShortTime := regexp.MustCompile(`(\d+):(\d+):(\d+)\.(\d+)`)
-	if matches := reShortTime.FindStringSubmatch(t); len(matches) >= 5 {
-		h, _ := strconv.Atoi(matches[1])
-		min, _ := strconv.Atoi(matches[2])
-		sec, _ := strconv.Atoi(matches[3])
-		ms, _ := strconv.Atoi(matches[4])
-		return fmt.Sprintf("%02d:%02d:%02d,%03d", h, min, sec, ms)
+// convertSubToSRT: baca file .sub, deteksi dialeknya, kembalikan string SRT.
+func convertSubToSRT(path string) (string, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return "", err
 	}
-
-	// Default fallback
-	return "00:00:00,000"
+	return subToSRT(data)
 }
 
-// ======================================
-// 🔹 Helper: hapus semua tag HTML tapi pertahankan \n
-// ======================================
-func stripHTMLTags(s string) string {
-	s = strings.ReplaceAll(s, "<br>", "\n")
-	s = strings.ReplaceAll(s, "<br/>", "\n")
-	s = strings.ReplaceAll(s, "<br />", "\n")
-	re := regexp.MustCompile(`(?i)</?[^>]+>`)
-	return re.ReplaceAllString(s, "")
+// subToSRT: isi .sub (MicroDVD / SubViewer 2.0) → SRT, tanpa membaca file.
+func subToSRT(data []byte) (string, error) {
+	content := normalizeEOL(string(data))
+	switch detectSubDialect(content) {
+	case "microdvd":
+		return convertMicroDVDToSRT(content)
+	case "subviewer":
+		return convertSubViewerToSRT(content)
+	}
+	return "", fmt.Errorf("file .sub bukan MicroDVD maupun SubViewer 2.0")
 }
 
-// ======================================
-// 🔹 Fungsi utama: proses SRT ke ASS
-// ======================================
-func processSRT(input interface{}) string {
-	// [Kode processSRT tetap sama persis...]
-	var content []byte
-	switch v := input.(type) {
-	case string:
-		if strings.Contains(v, "\n") {
-			content = []byte(v)
-		} else {
-			data, err := os.ReadFile(v)
-			if err != nil {
-				panic(err)
+// convertMicroDVDToSRT: {start}{end}teks dengan nomor frame, | sebagai pemisah
+// baris. Baris pertama {1}{1}23.976 (konvensi umum) dibaca sebagai fps;
+// {y:i}/{y:b}/{y:u} jadi tag HTML, kode kontrol lain ({c:$..}, {f:..}) dibuang.
+func convertMicroDVDToSRT(content string) (string, error) {
+	var detected float64
+	var sb strings.Builder
+	counter, first := 1, true
+	for _, ln := range strings.Split(content, "\n") {
+		m := reMicroDVDLine.FindStringSubmatch(strings.TrimSpace(strings.TrimPrefix(ln, "\ufeff")))
+		if m == nil {
+			continue
+		}
+		if first {
+			first = false
+			// {1}{1}23.976: baris fps, bukan cue
+			if f, err := parseFPS(m[3]); err == nil && m[1] == m[2] {
+				detected = f
+				continue
 			}
-			content = data
 		}
-	default:
-		panic("input tidak valid untuk processSRT()")
-	}
-
-	reFontOpen := regexp.MustCompile(`(?i)<font[^>]*>`)
-	reFontClose := regexp.MustCompile(`(?i)</font>`)
-	reBOpen := regexp.MustCompile(`(?i)<b>`)
-	reBClose := regexp.MustCompile(`(?i)</b>`)
-	reIOpen := regexp.MustCompile(`(?i)<i>`)
-	reIClose := regexp.MustCompile(`(?i)</i>`)
-	reUOpen := regexp.MustCompile(`(?i)<u>`)
-	reUClose := regexp.MustCompile(`(?i)</u>`)
-	reSOpen := regexp.MustCompile(`(?i)<s>`)
-	reSClose := regexp.MustCompile(`(?i)</s>`)
-	reAnyTag := regexp.MustCompile(`(?i)</?[^>]+>`)
-	reTiming := regexp.MustCompile(`(\d+):(\d+):(\d+),(\d+)`)
-
-	type Dialogue struct {
-		Start, End string
-		Style      string
-		Text       string
-	}
-
-	srtTimeToASSTime := func(s string) string {
-		matches := reTiming.FindStringSubmatch(s)
-		if len(matches) < 5 {
-			return "0:00:00.00"
+		fps := resolveFPS(detected)
+		start, _ := strconv.Atoi(m[1])
+		end, err := strconv.Atoi(m[2])
+		if err != nil {
+			end = start // {123}{} = frame akhir tidak diketahui, dibuang validateCue
 		}
-		h, _ := strconv.Atoi(matches[1])
-		m, _ := strconv.Atoi(matches[2])
-		si, _ := strconv.Atoi(matches[3])
-		ms, _ := strconv.Atoi(matches[4])
-		return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, si, ms/10)
-	}
-
-	extractColorAttr := func(s string) string {
-		s = strings.ToLower(s)
-		if strings.Contains(s, "color=") {
-			idx := strings.Index(s, "color=")
-			after := s[idx+6:]
-			after = strings.TrimLeft(after, " \t")
-			if len(after) == 0 {
+		text := reMicroDVDCode.ReplaceAllStringFunc(m[3], func(code string) string {
+			c := reMicroDVDCode.FindStringSubmatch(code)
+			if strings.ToLower(c[1]) != "y" {
 				return ""
 			}
-			if after[0] == '"' || after[0] == '\'' {
-				q := after[0]
-				after = after[1:]
-				end := strings.IndexRune(after, rune(q))
-				if end != -1 {
-					return after[:end]
+			var tags string
+			for _, st := range strings.Split(strings.ToLower(c[2]), ",") {
+				if st = strings.TrimSpace(st); st == "i" || st == "b" || st == "u" {
+					tags += "<" + st + ">"
 				}
-			} else {
-				fields := strings.Fields(after)
-				return strings.Trim(fields[0], ">")
 			}
+			return tags
+		})
+		text = strings.TrimSpace(strings.ReplaceAll(text, "|", "\n"))
+		if text == "" {
+			continue
 		}
-		return ""
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter,
+			formatTime(float64(start)/fps), formatTime(float64(end)/fps), text))
+		counter++
 	}
+	if counter == 1 {
+		return "", fmt.Errorf("tidak ada baris MicroDVD valid")
+	}
+	verbosef("sub: MicroDVD, %d cue, fps %.3f", counter-1, resolveFPS(detected))
+	return sb.String(), nil
+}
 
-	convertTagsToASS := func(text string) string {
-		text = reFontOpen.ReplaceAllStringFunc(text, func(m string) string {
-			color := extractColorAttr(m)
-			if color != "" {
-				c := strings.TrimPrefix(color, "#")
-				if len(c) == 6 {
-					rr := c[0:2]
-					gg := c[2:4]
-					bb := c[4:6]
-					return fmt.Sprintf("{\\c&H%s%s%s&}", bb, gg, rr)
-				}
-			}
-			return ""
-		})
-		text = reFontClose.ReplaceAllString(text, "")
-		text = regexp.MustCompile(`\{\\f[ns][^}]*\}`).ReplaceAllString(text, "")
-		text = reBOpen.ReplaceAllString(text, "{\\b1}")
-		text = reBClose.ReplaceAllString(text, "{\\b0}")
-		text = reIOpen.ReplaceAllString(text, "{\\i1}")
-		text = reIClose.ReplaceAllString(text, "{\\i0}")
-		text = reUOpen.ReplaceAllString(text, "{\\u1}")
-		text = reUClose.ReplaceAllString(text, "{\\u0}")
-		text = reSOpen.ReplaceAllString(text, "{\\s1}")
-		text = reSClose.ReplaceAllString(text, "{\\s0}")
-		text = reAnyTag.ReplaceAllString(text, "")
-		text = strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(text, " "))
-		return text
-	}
-
-	defineStyle := func(text string) string {
-		clean := regexp.MustCompile(`(?i)\{\\[^}]+\}`).ReplaceAllString(text, "")
-		clean = strings.TrimSpace(clean)
-		if (strings.HasPrefix(clean, "(") && strings.HasSuffix(clean, ")")) ||
-			(strings.HasPrefix(clean, "[") && strings.HasSuffix(clean, "]")) {
-			return "tanda"
-		}
-		alpha := regexp.MustCompile(`[A-Z0-9\s[:punct:]]+$`)
-		if alpha.MatchString(clean) && strings.ToUpper(clean) == clean {
-			return "tanda"
-		}
-		return "Default"
+// convertSubViewerToSRT: header [INFORMATION] (dilewati), lalu baris
+// HH:MM:SS.hh,HH:MM:SS.hh diikuti teks dengan [br] sebagai pemisah baris.
+func convertSubViewerToSRT(content string) (string, error) {
+	toSec := func(g []string) float64 {
+		h, _ := strconv.Atoi(g[0])
+		m, _ := strconv.Atoi(g[1])
+		sec, _ := strconv.Atoi(g[2])
+		// pecahan detik: .hh (seperseratus) biasanya, tapi .h/.hhh juga ditemui
+		frac := parseFloatSafe("0."+g[3], 0)
+		return float64((h*60+m)*60+sec) + frac
 	}
-
-	lines := strings.Split(string(content), "\n")
-	var dialogs []Dialogue
-	i := 0
-	for i < len(lines) {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			i++
+	lines := strings.Split(content, "\n")
+	var sb strings.Builder
+	counter := 1
+	for i := 0; i < len(lines); i++ {
+		m := reSubViewerTime.FindStringSubmatch(strings.TrimSpace(strings.TrimPrefix(lines[i], "\ufeff")))
+		if m == nil {
 			continue
 		}
-		if reTiming.MatchString(line) {
-			timeParts := strings.Split(line, " --> ")
-			start := srtTimeToASSTime(timeParts[0])
-			end := srtTimeToASSTime(timeParts[1])
-			i++
-			var textLines []string
-			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
-				textLines = append(textLines, lines[i])
-				i++
-			}
-			for _, t := range textLines {
-				dialog := Dialogue{
-					Start: start,
-					End:   end,
-					Text:  convertTagsToASS(t),
-				}
-				dialog.Style = defineStyle(dialog.Text)
-				dialogs = append(dialogs, dialog)
-			}
-		} else {
+		var text []string
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
 			i++
+			text = append(text, reSubViewerBreak.ReplaceAllString(strings.TrimSpace(lines[i]), "\n"))
+		}
+		body := strings.TrimSpace(strings.Join(text, "\n"))
+		if body == "" {
+			continue
 		}
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter,
+			formatTime(toSec(m[1:5])), formatTime(toSec(m[5:9])), body))
+		counter++
+	}
+	if counter == 1 {
+		return "", fmt.Errorf("tidak ada cue SubViewer valid")
 	}
+	verbosef("sub: SubViewer 2.0, %d cue", counter-1)
+	return sb.String(), nil
+}
 
-	sort.Slice(dialogs, func(i, j int) bool {
-		if dialogs[i].Start == dialogs[j].Start {
-			if dialogs[i].End == dialogs[j].End {
-				return dialogs[i].Style < dialogs[j].Style
-			}
-			return dialogs[i].End < dialogs[j].End
+// ======================================
+// 🔹 Helper: fps untuk timecode berbasis frame
+// ======================================
+const defaultFPS = 25.0
+
+// ttmlFrameRate menghitung fps efektif dari ttp:frameRate dan
+// ttp:frameRateMultiplier ("1000 1001" -> 30 * 1000/1001). 0 jika tidak ada.
+func ttmlFrameRate(rate, multiplier string) float64 {
+	fps := parseFloatSafe(rate, 0)
+	if fps <= 0 {
+		return 0
+	}
+	if f := strings.Fields(multiplier); len(f) == 2 {
+		num, den := parseFloatSafe(f[0], 0), parseFloatSafe(f[1], 0)
+		if num > 0 && den > 0 {
+			fps = fps * num / den
 		}
-		return dialogs[i].Start < dialogs[j].Start
-	})
+	}
+	return fps
+}
 
-	var merged []Dialogue
-	for i := 0; i < len(dialogs); i++ {
-		curr := dialogs[i]
-		for j := i + 1; j < len(dialogs); j++ {
-			next := dialogs[j]
-			if curr.Style == next.Style && curr.Start == next.Start && curr.End == next.End {
-				if curr.Text != next.Text {
-					curr.Text += `\N` + next.Text
-				}
-				dialogs[j].Style = "__merged__"
-			} else if curr.Style == next.Style && curr.Text == next.Text && curr.End == next.Start {
-				curr.End = next.End
-				dialogs[j].Style = "__merged__"
-			}
+// resolveFPS memilih fps: metadata file (jika -fps-detect aktif),
+// lalu flag -fps, lalu default 25.
+func resolveFPS(detected float64) float64 {
+	if opts.FPSDetect && detected > 0 {
+		return detected
+	}
+	if opts.FPS > 0 {
+		return opts.FPS
+	}
+	return defaultFPS
+}
+
+var (
+	reTTMLFrames      = regexp.MustCompile(`^\s*(\d+):(\d+):(\d+):(\d+)(?:\.\d+)?\s*$`) // HH:MM:SS:FF
+	reTTMLFrameOffset = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)f\s*$`)                  // 123f
+)
+
+// ======================================
+// 🔹 Helper: TTML time → SRT time (DIPERBAIKI)
+// ======================================
+func ttmlTimeToSRT(t string, fps float64) string {
+	if fps <= 0 {
+		fps = defaultFPS
+	}
+
+	// Timecode frame (00:00:00:00) harus dicek sebelum HH:MM:SS,
+	// kalau tidak frame-nya ikut terbuang
+	if matches := reTTMLFrames.FindStringSubmatch(t); len(matches) >= 5 {
+		h, _ := strconv.Atoi(matches[1])
+		min, _ := strconv.Atoi(matches[2])
+		sec, _ := strconv.Atoi(matches[3])
+		frames, _ := strconv.Atoi(matches[4])
+		return formatTime(float64((h*60+min)*60+sec) + float64(frames)/fps)
+	}
+
+	// Offset dalam frame: "123f"
+	if matches := reTTMLFrameOffset.FindStringSubmatch(t); len(matches) >= 2 {
+		frames, _ := strconv.ParseFloat(matches[1], 64)
+		return formatTime(frames / fps)
+	}
+
+	// Coba format dengan milliseconds dulu: HH:MM:SS.ms
+	if matches := reTimeFull.FindStringSubmatch(t); len(matches) >= 5 {
+		h, _ := strconv.Atoi(matches[1])
+		min, _ := strconv.Atoi(matches[2])
+		sec, _ := strconv.Atoi(matches[3])
+		ms, _ := strconv.Atoi(matches[4])
+		return fmt.Sprintf("%02d:%02d:%02d,%03d", h, min, sec, ms)
+	}
+
+	// Coba format tanpa milliseconds: HH:MM:SS
+	if matches := reTimeNoMS.FindStringSubmatch(t); len(matches) >= 4 {
+		h, _ := strconv.Atoi(matches[1])
+		min, _ := strconv.Atoi(matches[2])
+		sec, _ := strconv.Atoi(matches[3])
+		return fmt.Sprintf("%02d:%02d:%02d,000", h, min, sec)
+	}
+
+	// Default fallback
+	return "00:00:00,000"
+}
+
+// ======================================
+// 🔹 Helper: teks <p> TTML (innerxml) → teks polos
+// ======================================
+var (
+	reCDATA = regexp.MustCompile(`(?s)<!\[CDATA\[(.*?)\]\]>`)
+	// semua bentuk break: <br>, <br/>, <br />, <br></br>, <tt:br/>, huruf besar
+	reBreakTag = regexp.MustCompile(`(?i)<(?:[\w-]+:)?br\b[^>]*>(?:\s*</(?:[\w-]+:)?br\s*>)?`)
+)
+
+// ttmlParagraphText: tag asli (span, br) dibuang dulu, baru entity di-unescape,
+// jadi &lt;p&gt; yang memang teks tetap jadi teks. Isi CDATA tidak di-strip.
+//
+// Di luar CDATA, enter/indentasi mentah hanya whitespace XML (jadi satu spasi);
+// pindah baris hanya dari <br>, di level mana pun (mis. di dalam <span>).
+// Baris kosong dari <br/><br/> dijaga sebagai U+00A0 (jadi \h di ASS), karena
+// baris kosong sungguhan akan memutus cue di SRT perantara.
+func ttmlParagraphText(inner string) string {
+	var sb strings.Builder
+	strip := func(seg string) string {
+		return deepUnescapeHTML(stripHTMLTags(reWhitespace.ReplaceAllString(seg, " ")))
+	}
+	last := 0
+	for _, loc := range reCDATA.FindAllStringSubmatchIndex(inner, -1) {
+		sb.WriteString(strip(inner[last:loc[0]]))
+		sb.WriteString(deepUnescapeHTML(inner[loc[2]:loc[3]]))
+		last = loc[1]
+	}
+	sb.WriteString(strip(inner[last:]))
+
+	lines := strings.Split(sb.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	// break di awal/akhir paragraf tidak berarti apa-apa
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = "\u00a0"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ======================================
+// 🔹 Helper: hapus semua tag HTML tapi pertahankan \n
+// ======================================
+func stripHTMLTags(s string) string {
+	s = reBreakTag.ReplaceAllString(s, "\n")
+	return reAnyTag.ReplaceAllString(s, "")
+}
+
+// ======================================
+// 🔹 Deteksi tanda (sign) multi-sinyal
+// ======================================
+
+// signWeights: bobot tiap sinyal. Cue dianggap tanda jika total skor >= Threshold.
+type signWeights struct {
+	Caps       float64 // semua huruf kapital
+	Bracket    float64 // diapit () atau []
+	Positioned float64 // punya \pos, \move, atau \an selain 2
+	Short      float64 // durasi di bawah ShortMs
+	NoPunct    float64 // tidak ada tanda baca kalimat
+	ShortMs    int
+	Threshold  float64
+}
+
+var defaultSignWeights = signWeights{
+	Caps:       1,
+	Bracket:    2,
+	Positioned: 2,
+	Short:      0.5,
+	NoPunct:    0.5,
+	ShortMs:    1500,
+	Threshold:  2,
+}
+
+var (
+	reSignPositioned = regexp.MustCompile(`\\(pos|move)\(|\\an[13-9]|\\a(1[01]?|[35679])\b`)
+	reSignOverride   = regexp.MustCompile(`\{[^}]*\}`)
+	reSignLetter     = regexp.MustCompile(`\pL`)
+)
+
+// parseSignWeights membaca format "caps=1,bracket=2,pos=2,short=0.5,nopunct=0.5,shortms=1500,threshold=2".
+// Kunci yang tidak disebut memakai nilai default.
+func parseSignWeights(s string) (signWeights, error) {
+	w := defaultSignWeights
+	for _, kv := range strings.Split(s, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return w, fmt.Errorf("bobot tanda tidak valid: %q", kv)
 		}
-		if curr.Style != "__merged__" {
-			merged = append(merged, curr)
+		v, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return w, fmt.Errorf("bobot tanda tidak valid: %q", kv)
+		}
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case "caps":
+			w.Caps = v
+		case "bracket":
+			w.Bracket = v
+		case "pos", "positioned":
+			w.Positioned = v
+		case "short":
+			w.Short = v
+		case "nopunct":
+			w.NoPunct = v
+		case "shortms":
+			w.ShortMs = int(v)
+		case "threshold":
+			w.Threshold = v
+		default:
+			return w, fmt.Errorf("kunci bobot tanda tidak dikenal: %q", parts[0])
 		}
 	}
+	return w, nil
+}
 
-	sort.SliceStable(merged, func(i, j int) bool {
-		if merged[i].Style == "tanda" && merged[j].Style != "tanda" {
-			return true
+// signScore menjumlahkan bobot sinyal yang cocok untuk satu cue.
+// text boleh masih berisi override ASS ({\an8}, {\pos(...)}), durMs = durasi cue.
+func signScore(text string, durMs int, w signWeights) float64 {
+	score := 0.0
+	if reSignPositioned.MatchString(text) {
+		score += w.Positioned
+	}
+	clean := strings.TrimSpace(reSignOverride.ReplaceAllString(text, ""))
+	clean = strings.ReplaceAll(clean, `\N`, " ")
+	if clean == "" {
+		return score
+	}
+	if (strings.HasPrefix(clean, "(") && strings.HasSuffix(clean, ")")) ||
+		(strings.HasPrefix(clean, "[") && strings.HasSuffix(clean, "]")) {
+		score += w.Bracket
+	}
+	if looksLikeCapsSign(clean) {
+		score += w.Caps
+	}
+	if durMs > 0 && durMs < w.ShortMs {
+		score += w.Short
+	}
+	if reSignLetter.MatchString(clean) && !strings.ContainsAny(clean, ".,!?;:…。、！？，") {
+		score += w.NoPunct
+	}
+	return score
+}
+
+// Batas heuristik huruf kapital: singkatan pendek (FBI, NASA) dan baris
+// yang isinya kebanyakan angka/simbol tidak dianggap tanda.
+const (
+	tandaMinLetters     = 5
+	tandaMinLetterRatio = 0.6
+)
+
+// looksLikeCapsSign: semua huruf kapital, cukup panjang, dan mayoritas huruf.
+// Huruf kapital hanya berarti untuk aksara yang punya huruf besar/kecil.
+func looksLikeCapsSign(clean string) bool {
+	if strings.ToUpper(clean) != clean || strings.ToLower(clean) == clean {
+		return false
+	}
+	letters, visible := 0, 0
+	for _, r := range clean {
+		if unicode.IsSpace(r) {
+			continue
 		}
-		if merged[i].Style != "tanda" && merged[j].Style == "tanda" {
-			return false
+		visible++
+		if unicode.IsLetter(r) {
+			letters++
 		}
-		return merged[i].Start < merged[j].Start
-	})
+	}
+	return letters >= tandaMinLetters && float64(letters) >= tandaMinLetterRatio*float64(visible)
+}
+
+// isSignCue: true jika skor cue mencapai ambang.
+func isSignCue(text string, durMs int, w signWeights) bool {
+	return signScore(text, durMs, w) >= w.Threshold
+}
 
-	header := `[Script Info]
+// ======================================
+// 🔹 Header ASS Limenime (dipakai processSRT & import cue sheet)
+// ======================================
+const limenimeASSHeader = `[Script Info]
 ; Script generated by Limesub v3
 ; https://t.me/s/limenime
 ; https://www.facebook.com/limenime.official
@@ -1196,339 +2275,3981 @@ Style: Default,Basic Comical NC,70,&H00FFFFFF,&H00FFFFFF,&H00000000,&H80000000,0
 Style: Default Above,Basic Comical NC,70,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,-1,0,0,0,100,100,0,0,1,1.5,1,8,0,0,65,1
 Style: res,Basic Comical NC,1080,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,0,0,0,0,1,2,2,2,10,10,10,1
 Style: tanda,Basic Comical NC,75,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,-1,0,0,0,100,100,0,0,1,1,0,8,0,0,0,1
+Style: song,Basic Comical NC,60,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,-1,0,0,100,100,0,0,1,1.5,1,8,64,64,33,1
 
 [Events]
 Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text`
 
-	var sb strings.Builder
-	sb.WriteString(header + "\n")
-	for _, d := range merged {
-		text := d.Text
-		if d.Style == "Default" {
-			text = "{\\blur3}{\\fad(00,40)}" + text
-		}
-		sb.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,,0000,0000,0000,,%s\n",
-			d.Start, d.End, d.Style, text))
-	}
-	return sb.String()
-}
-
-// ======================================
-// 🔹 JSON parsers & detection (Bilibili & YouTube)
-// ======================================
+// assHeaderTemplate adalah header yang ditulis processSRT & import cue sheet;
+// diganti loadHeaderTemplate jika -header-file / "header_file" diisi.
+var assHeaderTemplate = limenimeASSHeader
 
-// Bili JSON structure (common)
-type biliBodyEntry struct {
-	From     float64 `json:"from"`
-	To       float64 `json:"to"`
-	Location int     `json:"location,omitempty"`
-	Content  string  `json:"content"`
-}
-type biliJSON struct {
-	Body []biliBodyEntry `json:"body"`
-}
+// requiredHeaderStyles: style yang dirujuk langsung oleh logika konversi.
+// "song" opsional: tanpa style itu lirik ditulis sebagai Default.
+var requiredHeaderStyles = []string{"Default", "Default Above", "tanda", "res"}
 
-// YouTube JSON structure (common shape)
-type ytSeg struct {
-	UTF8 string `json:"utf8"`
-}
-type ytEvent struct {
-	TStartMs   float64 `json:"tStartMs"`   // can be integer or float in JSON -> use float64
-	DDurationMs float64 `json:"dDurationMs"` // duration in ms
-	Segs       []ytSeg `json:"segs"`
-}
-type ytJSON struct {
-	Events []ytEvent `json:"events"`
+// headerHasStyle: header ASS punya baris Style: bernama name.
+func headerHasStyle(header, name string) bool {
+	for _, line := range strings.Split(header, "\n") {
+		if strings.HasPrefix(line, "Style:") && styleName(line) == name {
+			return true
+		}
+	}
+	return false
 }
 
-// convertJSONtoSRT: baca file .json, deteksi format, kembalikan string SRT
-func convertJSONtoSRT(path string) (string, error) {
+// loadHeaderTemplate membaca template header ASS milik grup sendiri. Template
+// harus diakhiri baris Format: di [Events] (Dialogue ditulis tepat setelahnya)
+// dan mendefinisikan semua requiredHeaderStyles.
+func loadHeaderTemplate(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("gagal membaca template header: %w", err)
 	}
-	text := strings.TrimSpace(string(data))
-
-	// Quick detection based on keys
-	lower := strings.ToLower(text)
-	if strings.Contains(lower, `"body"`) && (strings.Contains(lower, `"from"`) || strings.Contains(lower, `"content"`)) {
-		// Bilibili-like
-		var b biliJSON
-		if err := json.Unmarshal(data, &b); err != nil {
-			// fallback: try to decode ignoring unknown fields
-			return "", fmt.Errorf("gagal parse JSON Bilibili: %v", err)
-		}
-		var sb strings.Builder
-		counter := 1
-		for _, it := range b.Body {
-			// Guard: ensure valid times
-			start := it.From
-			end := it.To
-			if end <= 0 || end <= start {
-				// skip invalid entry
-				continue
-			}
-			startS := formatTime(start)
-			endS := formatTime(end)
-			// replace newlines with SRT linebreaks
-			content := strings.ReplaceAll(strings.TrimSpace(it.Content), "\n", "\n")
-			sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter, startS, endS, content))
-			counter++
-		}
-		if sb.Len() == 0 {
-			return "", fmt.Errorf("tidak ada caption valid ditemukan di Bilibili JSON")
-		}
-		return sb.String(), nil
-	} else if strings.Contains(lower, `"events"`) && strings.Contains(lower, `"tstartms"`) {
-		// YouTube-like
-		var y ytJSON
-		if err := json.Unmarshal(data, &y); err != nil {
-			return "", fmt.Errorf("gagal parse JSON YouTube: %v", err)
-		}
-		type caption struct {
-			Start float64
-			End   float64
-			Text  string
+	header := strings.TrimPrefix(string(data), "\ufeff")
+	header = strings.ReplaceAll(header, "\r\n", "\n")
+	header = strings.TrimRight(header, "\n\t ")
+
+	section := ""
+	styles := map[string]bool{}
+	last := ""
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
 		}
-		var caps []caption
-		for _, ev := range y.Events {
-			if len(ev.Segs) == 0 {
-				continue
-			}
-			start := ev.TStartMs / 1000.0
-			end := (ev.TStartMs + ev.DDurationMs) / 1000.0
-			parts := make([]string, 0, len(ev.Segs))
-			for _, s := range ev.Segs {
-				parts = append(parts, strings.TrimSpace(s.UTF8))
-			}
-			txt := strings.Join(parts, "")
-			// skip empty
-			if strings.TrimSpace(txt) == "" {
-				continue
-			}
-			caps = append(caps, caption{Start: start, End: end, Text: txt})
+		last = line
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			continue
 		}
-		if len(caps) == 0 {
-			return "", fmt.Errorf("tidak ada caption valid ditemukan di YouTube JSON")
+		if section == "[v4+ styles]" && strings.HasPrefix(line, "Style:") {
+			styles[styleName(line)] = true
 		}
-		// sort by start
-		sort.Slice(caps, func(i, j int) bool { return caps[i].Start < caps[j].Start })
-		var sb strings.Builder
-		for i, c := range caps {
-			sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, formatTime(c.Start), formatTime(c.End), strings.ReplaceAll(c.Text, "\n", "\n")))
+		if section == "[events]" && !strings.HasPrefix(line, "Format:") {
+			return "", fmt.Errorf("template header %s: [Events] hanya boleh berisi baris Format:", path)
 		}
-		return sb.String(), nil
 	}
-
-	// If not matched, attempt to decode generically:
-	var probe map[string]interface{}
-	if err := json.Unmarshal(data, &probe); err != nil {
-		return "", fmt.Errorf("format JSON tidak dikenali dan gagal decode: %v", err)
+	if section != "[events]" || !strings.HasPrefix(last, "Format:") {
+		return "", fmt.Errorf("template header %s harus diakhiri [Events] dan baris Format:", path)
 	}
-	// try search keys
-	if _, ok := probe["body"]; ok {
-		// try to unmarshal as bili
-		var b biliJSON
-		if err := json.Unmarshal(data, &b); err == nil && len(b.Body) > 0 {
-			var sb strings.Builder
-			counter := 1
-			for _, it := range b.Body {
-				startS := formatTime(it.From)
-				endS := formatTime(it.To)
-				sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter, startS, endS, strings.TrimSpace(it.Content)))
-				counter++
-			}
-			if sb.Len() > 0 {
-				return sb.String(), nil
-			}
+	var missing []string
+	for _, name := range requiredHeaderStyles {
+		// boleh memakai nama bawaan atau nama pengganti dari "style_names"
+		if !styles[name] && !styles[cfg.outStyle(name)] {
+			missing = append(missing, name)
 		}
 	}
-	if _, ok := probe["events"]; ok {
-		var y ytJSON
-		if err := json.Unmarshal(data, &y); err == nil && len(y.Events) > 0 {
-			type caption struct {
-				Start float64
-				End   float64
-				Text  string
-			}
-			var caps []caption
-			for _, ev := range y.Events {
-				if len(ev.Segs) == 0 {
-					continue
-				}
-				start := ev.TStartMs / 1000.0
-				end := (ev.TStartMs + ev.DDurationMs) / 1000.0
-				parts := make([]string, 0, len(ev.Segs))
-				for _, s := range ev.Segs {
-					parts = append(parts, strings.TrimSpace(s.UTF8))
-				}
-				txt := strings.Join(parts, "")
-				if strings.TrimSpace(txt) == "" {
-					continue
-				}
-				caps = append(caps, caption{Start: start, End: end, Text: txt})
-			}
-			sort.Slice(caps, func(i, j int) bool { return caps[i].Start < caps[j].Start })
-			var sb strings.Builder
-			for i, c := range caps {
-				sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, formatTime(c.Start), formatTime(c.End), strings.TrimSpace(c.Text)))
-			}
-			if sb.Len() > 0 {
-				return sb.String(), nil
-			}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template header %s tidak mendefinisikan style: %s", path, strings.Join(missing, ", "))
+	}
+	return header, nil
+}
+
+// htmlNamedColors: nama warna HTML (16 dasar + yang umum di SRT) → #rrggbb.
+// Nama yang tidak dikenal tetap jatuh ke penghapusan tag <font>.
+var htmlNamedColors = map[string]string{
+	"black":      "#000000",
+	"silver":     "#c0c0c0",
+	"gray":       "#808080",
+	"grey":       "#808080",
+	"white":      "#ffffff",
+	"maroon":     "#800000",
+	"red":        "#ff0000",
+	"purple":     "#800080",
+	"fuchsia":    "#ff00ff",
+	"magenta":    "#ff00ff",
+	"green":      "#008000",
+	"lime":       "#00ff00",
+	"olive":      "#808000",
+	"yellow":     "#ffff00",
+	"navy":       "#000080",
+	"blue":       "#0000ff",
+	"teal":       "#008080",
+	"aqua":       "#00ffff",
+	"cyan":       "#00ffff",
+	"orange":     "#ffa500",
+	"pink":       "#ffc0cb",
+	"brown":      "#a52a2a",
+	"gold":       "#ffd700",
+	"violet":     "#ee82ee",
+	"indigo":     "#4b0082",
+	"lightblue":  "#add8e6",
+	"lightgreen": "#90ee90",
+	"darkred":    "#8b0000",
+	"darkblue":   "#00008b",
+	"darkgreen":  "#006400",
+}
+
+var (
+	reRepeatedBreak = regexp.MustCompile(`(?:\\[Nn][ \t]*){2,}`)
+	reEdgeBreak     = regexp.MustCompile(`^(?:\s|\\[Nn])+|(?:\s|\\[Nn])+$`)
+)
+
+// joinSources: sumber cue hasil merge, tanpa duplikat (satu cue SRT bisa
+// menghasilkan beberapa baris yang digabung lagi).
+func joinSources(a, b string) string {
+	for _, s := range strings.Split(a, "; ") {
+		if s == b {
+			return a
 		}
 	}
+	return a + "; " + b
+}
 
-	return "", fmt.Errorf("format JSON tidak dikenali atau tidak ada caption")
+// assLineBreak: pemisah baris untuk teks yang digabung, \N (hard, default) atau
+// \n (soft: renderer hanya memutus baris jika perlu) sesuai -linebreak.
+func assLineBreak() string {
+	if opts.LineBreak == "soft" {
+		return `\n`
+	}
+	return `\N`
 }
 
-// formatTime: seconds (float) -> SRT timestamp (HH:MM:SS,mmm)
-func formatTime(seconds float64) string {
-	if seconds < 0 {
-		seconds = 0
+// joinASSLines menggabung dua potong teks dengan satu pemisah assLineBreak:
+// pemisah (\N maupun \n) di ujung tiap potong dibuang dan pemisah beruntun
+// diringkas, supaya tidak ada baris kosong.
+func joinASSLines(a, b string) string {
+	a = reEdgeBreak.ReplaceAllString(a, "")
+	b = reEdgeBreak.ReplaceAllString(b, "")
+	if a == "" || b == "" {
+		return a + b
 	}
-	totalMs := int(seconds*1000 + 0.5)
-	h := totalMs / 3600000
-	totalMs %= 3600000
-	m := totalMs / 60000
-	totalMs %= 60000
-	s := totalMs / 1000
-	ms := totalMs % 1000
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+	return collapseBreaks(a) + assLineBreak() + collapseBreaks(b)
 }
 
-func safeDialogMessage(title, msg string, isError bool) {
-	defer func() {
-		if r := recover(); r != nil {
-			// fallback ke terminal jika library dialog gagal
-			if isError {
+// collapseBreaks: pemisah beruntun jadi satu; \N jika salah satunya hard.
+func collapseBreaks(s string) string {
+	return reRepeatedBreak.ReplaceAllStringFunc(s, func(run string) string {
+		if strings.Contains(run, `\N`) {
+			return `\N`
+		}
+		return `\n`
+	})
+}
+
+var nbspToHardSpace = strings.NewReplacer("\u00a0", `\h`, "&nbsp;", `\h`, "&#160;", `\h`, "&#xa0;", `\h`, "&#xA0;", `\h`)
+
+// cueReport: masalah di input yang ditemukan saat membangun cue ASS.
+type cueReport struct {
+	BadTiming []int // nomor baris (1-based) timing yang gagal di-parse
+	Dropped   int   // cue yang dibuang validateCue (durasi nol/negatif/terlalu pendek)
+}
+
+// summary: "" jika tidak ada masalah.
+func (r cueReport) summary() string {
+	var warns []string
+	if len(r.BadTiming) > 0 {
+		var nums []string
+		for k, n := range r.BadTiming {
+			if k == 5 {
+				nums = append(nums, "...")
+				break
+			}
+			nums = append(nums, strconv.Itoa(n))
+		}
+		action := "waktu yang rusak dijadikan 0:00:00.00"
+		if opts.Strict {
+			action = "cue-nya dibuang"
+		}
+		warns = append(warns, fmt.Sprintf("peringatan: %d baris timing tidak valid, %s (baris %s)",
+			len(r.BadTiming), action, strings.Join(nums, ", ")))
+	}
+	if r.Dropped > 0 {
+		warns = append(warns, fmt.Sprintf("peringatan: %d cue dibuang karena durasinya nol, negatif, atau di bawah -min-duration", r.Dropped))
+	}
+	return strings.Join(warns, "\n")
+}
+
+// validateCue: nil jika cue layak ditulis. Dipakai semua jalur (SRT hasil
+// konversi apa pun dan cue sheet), jadi aturan buang cue sama di mana-mana.
+func validateCue(startMs, endMs int) error {
+	if endMs <= startMs {
+		return fmt.Errorf("durasi nol atau negatif (%d ms)", endMs-startMs)
+	}
+	if minMs := int(opts.MinDuration / time.Millisecond); minMs > 0 && endMs-startMs < minMs {
+		return fmt.Errorf("durasi %d ms di bawah -min-duration", endMs-startMs)
+	}
+	return nil
+}
+
+// srtCue: satu cue SRT mentah hasil scanSRTCues.
+type srtCue struct {
+	Line       int    // nomor baris timing di sumber (mulai 1)
+	No         int    // nomor cue dari baris indeks sumber, atau urutan jika tidak ada
+	Timing     string // baris timing apa adanya
+	Start, End string // kedua sisi " --> ", tanpa koordinat
+	Coords     []int  // X1, X2, Y1, Y2 dari ekstensi koordinat SubRip, nil jika tidak ada
+	Text       []string
+}
+
+// scanSRTCues memecah SRT jadi cue. Dipakai processSRT dan -to srt, jadi
+// aturan baris indeks/timing/teks sama di keduanya.
+func scanSRTCues(content string) []srtCue {
+	lines := strings.Split(content, "\n")
+
+	// baris timing harus punya pemisah " --> ", supaya teks yang kebetulan
+	// berisi timestamp (mis. jam di layar) tidak dianggap timing
+	isTimingLine := func(s string) bool {
+		return strings.Contains(s, " --> ") && reSRTTime.MatchString(s)
+	}
+	// angka polos hanya dianggap nomor cue jika baris berikutnya timing
+	isCueIndex := func(idx int) bool {
+		if idx+1 >= len(lines) || !reCueIndex.MatchString(strings.TrimSpace(lines[idx])) {
+			return false
+		}
+		return isTimingLine(strings.TrimSpace(lines[idx+1]))
+	}
+
+	var cues []srtCue
+	cueNo := 1
+	i := 0
+	for i < len(lines) {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			i++
+			continue
+		}
+		if isCueIndex(i) {
+			cueNo, _ = strconv.Atoi(line)
+			i++
+			continue
+		}
+		if !isTimingLine(line) {
+			i++
+			continue
+		}
+		timeParts := strings.SplitN(line, " --> ", 2)
+		c := srtCue{Line: i + 1, No: cueNo, Timing: line, Start: timeParts[0], End: timeParts[1]}
+		// "--> 00:00:04,000 X1:100 X2:200 Y1:300 Y2:400": koordinat dipisah dulu
+		// supaya tidak ikut terbaca sebagai bagian waktu akhir
+		if m := reSRTCoords.FindStringSubmatch(c.End); m != nil {
+			c.End = c.End[:len(c.End)-len(m[0])]
+			for _, v := range m[1:] {
+				n, _ := strconv.Atoi(v)
+				c.Coords = append(c.Coords, n)
+			}
+		}
+		cueNo++
+		i++
+		// trim ASCII saja: baris berisi U+00A0 (baris kosong dari TTML) bukan pemisah cue
+		for i < len(lines) && strings.Trim(lines[i], " \t\r") != "" {
+			// cue berikutnya tanpa baris kosong pemisah
+			if isTimingLine(strings.TrimSpace(lines[i])) || isCueIndex(i) {
+				break
+			}
+			c.Text = append(c.Text, lines[i])
+			i++
+		}
+		cues = append(cues, c)
+	}
+	return cues
+}
+
+// valid mencatat timing rusak / cue yang dibuang ke report dan mengembalikan
+// false jika cue tidak ditulis (timing rusak dengan -strict, atau validateCue).
+func (c srtCue) valid(report *cueReport) bool {
+	badTiming := !reTimingValid.MatchString(strings.TrimSpace(c.Start)) ||
+		!reTimingValid.MatchString(strings.TrimSpace(c.End))
+	if badTiming {
+		report.BadTiming = append(report.BadTiming, c.Line)
+		if opts.Strict {
+			return false
+		}
+	}
+	if err := validateCue(srtTimeToMs(c.Start), srtTimeToMs(c.End)); err != nil {
+		report.Dropped++
+		return false
+	}
+	return true
+}
+
+// cleanSRT (-to srt) menulis ulang SRT dengan parser yang sama seperti
+// processSRT, tanpa membangun ASS: cue tanpa teks dibuang, nomor diurutkan
+// ulang dari 1, dan timestamp ditulis kanonik HH:MM:SS,mmm.
+func cleanSRT(srt string) (string, cueReport) {
+	var report cueReport
+	var sb strings.Builder
+	n := 1
+	for _, c := range scanSRTCues(normalizeEOL(srt)) {
+		var text []string
+		for _, t := range c.Text {
+			if t = strings.TrimRight(t, " \t\r"); strings.Trim(t, " \t") != "" {
+				text = append(text, t)
+			}
+		}
+		if len(text) == 0 {
+			verbosef("to srt: cue #%d (baris %d) kosong, dibuang", c.No, c.Line)
+			continue
+		}
+		if !c.valid(&report) {
+			continue
+		}
+		coords := ""
+		if c.Coords != nil {
+			coords = fmt.Sprintf(" X1:%d X2:%d Y1:%d Y2:%d", c.Coords[0], c.Coords[1], c.Coords[2], c.Coords[3])
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s%s\n%s\n\n", n,
+			formatTime(float64(srtTimeToMs(c.Start))/1000), formatTime(float64(srtTimeToMs(c.End))/1000),
+			coords, strings.Join(text, "\n"))
+		n++
+	}
+	return sb.String(), report
+}
+
+// ======================================
+// 🔹 Fungsi utama: proses SRT ke ASS
+// ======================================
+func processSRT(input interface{}) string {
+	out, _ := processSRTReport(input)
+	return out
+}
+
+// ======================================
+// 🔹 Helper: konversi cue SRT
+// ======================================
+
+var (
+	reFontOpen        = regexp.MustCompile(`(?i)<font[^>]*>`)
+	reFontClose       = regexp.MustCompile(`(?i)</font>`)
+	reBOpen           = regexp.MustCompile(`(?i)<b>`)
+	reBClose          = regexp.MustCompile(`(?i)</b>`)
+	reIOpen           = regexp.MustCompile(`(?i)<i>`)
+	reIClose          = regexp.MustCompile(`(?i)</i>`)
+	reUOpen           = regexp.MustCompile(`(?i)<u>`)
+	reUClose          = regexp.MustCompile(`(?i)</u>`)
+	reSOpen           = regexp.MustCompile(`(?i)<s>`)
+	reSClose          = regexp.MustCompile(`(?i)</s>`)
+	reAnyTag          = regexp.MustCompile(`(?i)</?[^>]+>`)
+	reColorAttr       = regexp.MustCompile(`(?i)\bcolor\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>"'/]+))`)
+	reFmtTag          = regexp.MustCompile(`(?i)<(/?)(b|i|u|s|font)(?:\s[^>]*)?>`)
+	reSRTTime         = regexp.MustCompile(`(\d+):(\d+):(\d+),(\d+)`)
+	reSRTOverride     = regexp.MustCompile(`\{[^}]*\}`)
+	reSRTFontOverride = regexp.MustCompile(`\{\\f[ns][^}]*\}`)
+	reSRTTagBlock     = regexp.MustCompile(`(?i)\{\\[^}]+\}`)
+	reWhitespace      = regexp.MustCompile(`\s+`)
+	reCapsTail        = regexp.MustCompile(`[A-Z0-9\s[:punct:]]+$`)
+	reTimingValid     = regexp.MustCompile(`^(\d+):(\d+):(\d+),(\d+)`) // tiap sisi " --> " harus diawali timestamp utuh
+	reCueIndex        = regexp.MustCompile(`^\d+$`)
+	reTopAlign        = regexp.MustCompile(`\{[^}]*\\an[789][^}]*\}`)
+	reMarginTag       = regexp.MustCompile(`\{\\margin\((\d+),(\d+),(\d+)\)\}`)
+	reSRTCoords       = regexp.MustCompile(`(?i)\s+X1:\s*(-?\d+)\s+X2:\s*(-?\d+)\s+Y1:\s*(-?\d+)\s+Y2:\s*(-?\d+)\s*$`)
+)
+
+// srtTimeToASS mengubah timestamp SRT (HH:MM:SS,mmm) ke format ASS (H:MM:SS.cc).
+// Error jika tidak ada timestamp utuh di s; pemanggil yang memutuskan fallback-nya.
+func srtTimeToASS(s string) (string, error) {
+	matches := reSRTTime.FindStringSubmatch(s)
+	if len(matches) < 5 {
+		return "", fmt.Errorf("timestamp SRT tidak valid: %q", strings.TrimSpace(s))
+	}
+	h, _ := strconv.Atoi(matches[1])
+	m, _ := strconv.Atoi(matches[2])
+	si, _ := strconv.Atoi(matches[3])
+	ms, _ := strconv.Atoi(matches[4])
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, si, ms/10), nil
+}
+
+// srtTimeToMs mengubah timestamp SRT ke milidetik; 0 jika tidak valid.
+func srtTimeToMs(s string) int {
+	matches := reSRTTime.FindStringSubmatch(s)
+	if len(matches) < 5 {
+		return 0
+	}
+	h, _ := strconv.Atoi(matches[1])
+	m, _ := strconv.Atoi(matches[2])
+	si, _ := strconv.Atoi(matches[3])
+	ms, _ := strconv.Atoi(matches[4])
+	return ((h*60+m)*60+si)*1000 + ms
+}
+
+// extractColorAttr mengambil nilai atribut color= dari tag <font ...> (huruf kecil),
+// baik berkutip ganda, berkutip tunggal, maupun tanpa kutip.
+func extractColorAttr(s string) string {
+	m := reColorAttr.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(m[1] + m[2] + m[3]))
+}
+
+// normalizeHexColor: "#rgb", "#rrggbb" atau "#rrggbbaa" (# opsional) →
+// digit hex huruf besar, dengan #rgb diperluas ke 6 digit. ok=false jika bukan
+// hex yang valid.
+func normalizeHexColor(color string) (string, bool) {
+	c := strings.ToUpper(strings.TrimPrefix(color, "#"))
+	for i := 0; i < len(c); i++ {
+		if !strings.ContainsRune("0123456789ABCDEF", rune(c[i])) {
+			return "", false
+		}
+	}
+	switch len(c) {
+	case 3:
+		return string([]byte{c[0], c[0], c[1], c[1], c[2], c[2]}), true
+	case 6, 8:
+		return c, true
+	}
+	return "", false
+}
+
+// carryCueTags meneruskan tag format (<b>, <i>, <u>, <s>, <font>) yang masih
+// terbuka di akhir satu baris ke baris berikutnya dalam cue yang sama.
+// processSRT mengubah tiap baris teks jadi Dialogue sendiri, jadi tanpa ini
+// <i> di baris 1 yang ditutup di baris 2 jadi {\i1} tanpa penutup + {\i0} yatim.
+func carryCueTags(lines []string) []string {
+	var open []string // tag pembuka mentah, urut dibuka
+	out := make([]string, len(lines))
+	for n, ln := range lines {
+		prefix := strings.Join(open, "")
+		for _, m := range reFmtTag.FindAllStringSubmatch(ln, -1) {
+			if m[1] == "" {
+				open = append(open, m[0])
+				continue
+			}
+			// tutup pembuka terakhir dengan nama sama; penutup yatim dibiarkan
+			for k := len(open) - 1; k >= 0; k-- {
+				if strings.EqualFold(reFmtTag.FindStringSubmatch(open[k])[2], m[2]) {
+					open = append(open[:k], open[k+1:]...)
+					break
+				}
+			}
+		}
+		var suffix strings.Builder
+		for k := len(open) - 1; k >= 0; k-- {
+			suffix.WriteString("</" + strings.ToLower(reFmtTag.FindStringSubmatch(open[k])[2]) + ">")
+		}
+		out[n] = prefix + ln + suffix.String()
+	}
+	return out
+}
+
+// normalizeTags merapikan tag format HTML yang salah sarang atau tidak
+// ditutup (umum di subtitle hasil scrape): <font><b>x</font></b> jadi
+// <font><b>x</b></font>, tag yang masih terbuka ditutup di akhir, dan penutup
+// yatim dibuang. Tag baru ditulis tepat sebelum teks, jadi tidak ada pasangan kosong.
+func normalizeTags(text string) string {
+	name := func(raw string) string { return strings.ToLower(reFmtTag.FindStringSubmatch(raw)[2]) }
+	var want, emitted []string // tag pembuka mentah: yang seharusnya aktif vs yang sudah ditulis
+	var sb strings.Builder
+	reopen := func() {
+		p := 0
+		for p < len(want) && p < len(emitted) && want[p] == emitted[p] {
+			p++
+		}
+		for k := len(emitted) - 1; k >= p; k-- {
+			sb.WriteString("</" + name(emitted[k]) + ">")
+		}
+		emitted = append(emitted[:p], want[p:]...)
+		for _, raw := range want[p:] {
+			sb.WriteString(raw)
+		}
+	}
+	last := 0
+	for _, loc := range reFmtTag.FindAllStringSubmatchIndex(text, -1) {
+		if seg := text[last:loc[0]]; seg != "" {
+			reopen()
+			sb.WriteString(seg)
+		}
+		last = loc[1]
+		raw := text[loc[0]:loc[1]]
+		if loc[2] == loc[3] {
+			want = append(want, raw)
+			continue
+		}
+		for k := len(want) - 1; k >= 0; k-- {
+			if name(want[k]) == strings.ToLower(text[loc[4]:loc[5]]) {
+				want = append(want[:k:k], want[k+1:]...)
+				break
+			}
+		}
+	}
+	if seg := text[last:]; seg != "" {
+		reopen()
+		sb.WriteString(seg)
+	}
+	want = nil
+	reopen()
+	return sb.String()
+}
+
+// convertTagsToASS mengubah tag HTML SRT (<b>, <i>, <font color>, ...) ke
+// override ASS; override ASS yang sudah ada di sumber dibiarkan apa adanya.
+func convertTagsToASS(text string) string {
+	text = reSRTFontOverride.ReplaceAllString(text, "")
+	// override ASS yang sudah ada di sumber ({\c&H..&}, {\pos(..)}, ...) disimpan
+	// dulu supaya tidak tersentuh konversi HTML / peringkasan spasi
+	var blocks []string
+	text = reSRTOverride.ReplaceAllStringFunc(text, func(m string) string {
+		blocks = append(blocks, m)
+		return fmt.Sprintf("\x00%d\x00", len(blocks)-1)
+	})
+
+	text = normalizeTags(text)
+	text = reFontOpen.ReplaceAllStringFunc(text, func(m string) string {
+		color := extractColorAttr(m)
+		if hex, ok := htmlNamedColors[color]; ok {
+			color = hex
+		}
+		c, ok := normalizeHexColor(color)
+		if !ok {
+			return ""
+		}
+		// HTML urutannya RGB, ASS kebalikannya BGR
+		tag := fmt.Sprintf("\\c&H%s%s%s&", c[4:6], c[2:4], c[0:2])
+		if len(c) == 8 {
+			// alpha CSS: ff = opak; alpha ASS kebalikannya: 00 = opak
+			a, _ := strconv.ParseUint(c[6:8], 16, 8)
+			tag += fmt.Sprintf("\\1a&H%02X&", 255-a)
+		}
+		return "{" + tag + "}"
+	})
+	text = reFontClose.ReplaceAllString(text, "")
+	text = reBOpen.ReplaceAllString(text, "{\\b1}")
+	text = reBClose.ReplaceAllString(text, "{\\b0}")
+	text = reIOpen.ReplaceAllString(text, "{\\i1}")
+	text = reIClose.ReplaceAllString(text, "{\\i0}")
+	text = reUOpen.ReplaceAllString(text, "{\\u1}")
+	text = reUClose.ReplaceAllString(text, "{\\u0}")
+	text = reSOpen.ReplaceAllString(text, "{\\s1}")
+	text = reSClose.ReplaceAllString(text, "{\\s0}")
+	text = reAnyTag.ReplaceAllString(text, "")
+	// non-breaking space (&nbsp; / &#160; / U+00A0) sengaja dipakai untuk
+	// indentasi, jadi diubah ke hard space \h (tidak ikut dipangkas / diringkas)
+	text = nbspToHardSpace.Replace(text)
+	if opts.PreserveSpacing {
+		// spasi beruntun dibiarkan (ASCII-art / rata kolom); spasi awal jadi \h
+		// karena renderer ASS memangkasnya, sisa \r/spasi di akhir dibuang
+		text = strings.TrimRight(text, " \t\r\n")
+		indent := len(text) - len(strings.TrimLeft(text, " \t"))
+		text = strings.Repeat(`\h`, indent) + text[indent:]
+	} else {
+		text = strings.TrimSpace(reWhitespace.ReplaceAllString(text, " "))
+	}
+
+	return restorePlaceholders(text, blocks)
+}
+
+// defineStyle memilih style "Default", "tanda" atau "song" untuk teks cue yang
+// sudah dikonversi, mengikuti -no-tanda / -detect-sign.
+func defineStyle(text string, durMs int) string {
+	if opts.NoTanda {
+		return "Default"
+	}
+	if isSongCue(text) {
+		return "song"
+	}
+	if opts.DetectSign {
+		if isSignCue(text, durMs, opts.SignWeights) {
+			return "tanda"
+		}
+		return "Default"
+	}
+	clean := reSRTTagBlock.ReplaceAllString(text, "")
+	clean = strings.TrimSpace(clean)
+	if (strings.HasPrefix(clean, "(") && strings.HasSuffix(clean, ")")) ||
+		(strings.HasPrefix(clean, "[") && strings.HasSuffix(clean, "]")) {
+		return "tanda"
+	}
+	if reCapsTail.MatchString(clean) && looksLikeCapsSign(clean) {
+		return "tanda"
+	}
+	return "Default"
+}
+
+// ======================================
+// 🔹 Helper: teks RTL (Arab/Ibrani)
+// ======================================
+
+const rlm = "\u200f" // RIGHT-TO-LEFT MARK
+
+// isRTLText: mayoritas huruf di teks (tanpa override) beraksara kanan-ke-kiri.
+func isRTLText(text string) bool {
+	rtl, letters := 0, 0
+	for _, r := range reSRTOverride.ReplaceAllString(text, "") {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko) {
+			rtl++
+		}
+	}
+	return letters > 0 && rtl*2 > letters
+}
+
+// reRTLLineStart: awal teks atau pemisah baris, plus override di depan baris.
+var reRTLLineStart = regexp.MustCompile(`(?:^|\\[Nn])(?:\{[^}]*\})*`)
+
+// markRTL menaruh RLM di awal tiap baris (setelah override di depannya) supaya
+// tanda baca di ujung kalimat tidak pindah ke sisi yang salah; baris yang sudah
+// diawali kontrol bidi dibiarkan. Dengan -rtl-q2 ditambah {\q2}.
+func markRTL(text string) string {
+	var sb strings.Builder
+	if opts.RTLq2 {
+		sb.WriteString(`{\q2}`)
+	}
+	last := 0
+	for _, loc := range reRTLLineStart.FindAllStringIndex(text, -1) {
+		sb.WriteString(text[last:loc[1]])
+		if r, _ := utf8.DecodeRuneInString(text[loc[1]:]); !unicode.Is(unicode.Bidi_Control, r) {
+			sb.WriteString(rlm)
+		}
+		last = loc[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// isSongCue: lirik lagu (OP/ED/insert song) diawali ♪/♫, atau diapit # di
+// kedua ujung (# lirik #). # di awal saja tidak cukup ("#1", tagar).
+func isSongCue(text string) bool {
+	clean := strings.TrimSpace(reSRTOverride.ReplaceAllString(text, ""))
+	if strings.HasPrefix(clean, "♪") || strings.HasPrefix(clean, "♫") {
+		return true
+	}
+	return strings.HasPrefix(clean, "#") && strings.HasSuffix(clean, "#") && strings.Trim(clean, "# ") != ""
+}
+
+// srtDialog: satu baris Dialogue hasil processSRT sebelum ditulis.
+type srtDialog struct {
+	Start, End string
+	Style      string
+	Text       string
+	Margin     [3]int // L, R, V dari tag internal {\margin(L,R,V)}
+	Source     string // nomor & timing cue SRT asal, untuk -annotate
+}
+
+// mergeDialogs mengurutkan dialogs lalu menggabungkan cue yang waktunya sama
+// (teks disambung) atau teksnya sama dan bersambung (End diperpanjang, jeda
+// di bawah mergeGapMs masih dianggap bersambung). dialogs ikut terurut.
+func mergeDialogs(dialogs []srtDialog, mergeGapMs int) []srtDialog {
+	sort.Slice(dialogs, func(i, j int) bool {
+		if dialogs[i].Start == dialogs[j].Start {
+			if dialogs[i].End == dialogs[j].End {
+				return dialogs[i].Style < dialogs[j].Style
+			}
+			return dialogs[i].End < dialogs[j].End
+		}
+		return dialogs[i].Start < dialogs[j].Start
+	})
+
+	// jeda kecil antar segmen caption yang sama (artefak streaming) boleh digabung
+	withinGap := func(end, start string) bool {
+		if end == start {
+			return true
+		}
+		gap := assTimeToMs(start) - assTimeToMs(end)
+		return mergeGapMs > 0 && gap >= 0 && gap < mergeGapMs
+	}
+
+	// Satu kali jalan atas slice yang sudah terurut, dengan aturan yang sama
+	// seperti sebelumnya (cue paling awal yang cocok selalu menang):
+	// - waktu & style sama → teks digabung dengan \N (selalu bersebelahan setelah sort)
+	// - teks & style sama dan bersambung → End diperpanjang; kandidatnya dicari
+	//   lewat map style+teks, jadi tidak perlu loop bersarang
+	merged := make([]srtDialog, 0, len(dialogs))
+	open := map[string][]int{} // style+teks → indeks di merged (urut naik) yang masih bisa diperpanjang
+	key := func(d srtDialog) string { return d.Style + "\x00" + d.Text }
+	for _, d := range dialogs {
+		k := key(d)
+		startMs := assTimeToMs(d.Start)
+
+		// kandidat perpanjangan; yang End-nya sudah terlalu jauh dibuang
+		ext := -1
+		kept := open[k][:0]
+		for _, idx := range open[k] {
+			if assTimeToMs(merged[idx].End)+mergeGapMs < startMs {
+				continue
+			}
+			kept = append(kept, idx)
+			if ext < 0 && withinGap(merged[idx].End, d.Start) {
+				ext = idx
+			}
+		}
+		open[k] = kept
+
+		// kandidat gabung teks: hanya cue terakhir
+		same := -1
+		if n := len(merged); n > 0 {
+			last := merged[n-1]
+			if last.Style == d.Style && last.Start == d.Start && last.End == d.End {
+				same = n - 1
+			}
+		}
+
+		switch {
+		case same >= 0 && (ext < 0 || same < ext):
+			last := &merged[same]
+			if last.Text != d.Text {
+				lk := key(*last)
+				if l := open[lk]; len(l) > 0 && l[len(l)-1] == same {
+					open[lk] = l[:len(l)-1]
+				}
+				last.Text = joinASSLines(last.Text, d.Text)
+				last.Source = joinSources(last.Source, d.Source)
+				open[key(*last)] = append(open[key(*last)], same)
+			}
+		case ext >= 0:
+			merged[ext].End = d.End
+			merged[ext].Source = joinSources(merged[ext].Source, d.Source)
+		default:
+			merged = append(merged, d)
+			open[k] = append(open[k], len(merged)-1)
+		}
+	}
+
+	return merged
+}
+
+// processSRTReport sama dengan processSRT, ditambah laporan baris timing yang
+// rusak (mis. typo "00:00:0l,000"). Tanpa -strict cue tersebut tetap ditulis
+// dengan waktu nol seperti dulu; dengan -strict cue-nya dibuang.
+func processSRTReport(input interface{}) (string, cueReport) {
+	// [Kode processSRT tetap sama persis...]
+	var content []byte
+	switch v := input.(type) {
+	case []byte:
+		// isi SRT dari memori: tidak pernah dianggap path, walau kosong
+		content = v
+	case string:
+		// file CR-saja tidak punya \n sama sekali, jadi cek \r juga
+		if strings.ContainsAny(v, "\r\n") {
+			content = []byte(v)
+		} else {
+			data, err := readInput(v)
+			if err != nil {
+				panic(err)
+			}
+			content = data
+		}
+	default:
+		panic("input tidak valid untuk processSRTReport()")
+	}
+
+	header := cfg.assHeader()
+	hasSong := headerHasStyle(header, cfg.outStyle("song"))
+
+	var report cueReport
+	var dialogs []srtDialog
+	for _, c := range scanSRTCues(normalizeEOL(string(content))) {
+		if !c.valid(&report) {
+			continue
+		}
+		source := fmt.Sprintf("#%d %s", c.No, c.Timing)
+		// timing rusak tetap ditulis sebagai waktu nol (lihat BadTiming)
+		start, err := srtTimeToASS(c.Start)
+		if err != nil {
+			start = "0:00:00.00"
+		}
+		end, err := srtTimeToASS(c.End)
+		if err != nil {
+			end = "0:00:00.00"
+		}
+		durMs := srtTimeToMs(c.End) - srtTimeToMs(c.Start)
+		if opts.SRTCoords && c.Coords != nil && len(c.Text) > 0 {
+			// kotak X1..X2 × Y1..Y2: pojok kiri atasnya jadi titik jangkar \an7
+			c.Text[0] = fmt.Sprintf(`{\an7\pos(%d,%d)}`, c.Coords[0], c.Coords[2]) + c.Text[0]
+		}
+		// cue dengan \an7-9 (mis. dari VTT line:0) ditaruh di atas
+		topCue := reTopAlign.MatchString(strings.Join(c.Text, ""))
+		for _, t := range carryCueTags(c.Text) {
+			dialog := srtDialog{
+				Start:  start,
+				End:    end,
+				Source: source,
+			}
+			if m := reMarginTag.FindStringSubmatch(t); m != nil {
+				for k := range dialog.Margin {
+					dialog.Margin[k], _ = strconv.Atoi(m[k+1])
+				}
+				t = reMarginTag.ReplaceAllString(t, "")
+			}
+			dialog.Text = convertTagsToASS(t)
+			dialog.Style = defineStyle(dialog.Text, durMs)
+			if dialog.Style == "song" && !hasSong {
+				// template header sendiri tanpa style song
+				dialog.Style = "Default"
+			}
+			if topCue && dialog.Style == "Default" {
+				dialog.Style = "Default Above"
+			}
+			dialogs = append(dialogs, dialog)
+		}
+	}
+
+	merged := mergeDialogs(dialogs, int(opts.MergeGap/time.Millisecond))
+	verbosef("processSRT: %d baris Dialogue dari input, %d setelah merge", len(dialogs), len(merged))
+
+	// urutan kronologis; style hanya pembeda visual. -group-tanda mengembalikan
+	// perilaku lama (semua tanda dikumpulkan di atas)
+	sort.SliceStable(merged, func(i, j int) bool {
+		if opts.GroupTanda && (merged[i].Style == "tanda") != (merged[j].Style == "tanda") {
+			return merged[i].Style == "tanda"
+		}
+		return assTimeToMs(merged[i].Start) < assTimeToMs(merged[j].Start)
+	})
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, d := range merged {
+		if opts.Annotate {
+			sb.WriteString(fmt.Sprintf("Comment: 0,%s,%s,%s,,0000,0000,0000,,src %s\n",
+				d.Start, d.End, cfg.outStyle(d.Style), d.Source))
+		}
+		text := d.Text
+		if opts.RTL || isRTLText(text) {
+			text = markRTL(text)
+		}
+		if d.Style == "Default" {
+			text = opts.DefaultFX + text
+		}
+		sb.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,,%04d,%04d,%04d,,%s\n",
+			d.Start, d.End, cfg.outStyle(d.Style), d.Margin[0], d.Margin[1], d.Margin[2], text))
+	}
+	return sb.String(), report
+}
+
+// ======================================
+// 🔹 Model cue (untuk export ke format lain)
+// ======================================
+
+// Cue adalah satu event subtitle dengan waktu dalam milidetik.
+// Text masih berupa teks ASS (boleh berisi override block dan \N).
+type Cue struct {
+	Start, End int
+	Style      string
+	Text       string
+}
+
+// assDoc: hasil parse [Events] ASS beserta info yang dibutuhkan writer.
+type assDoc struct {
+	PlayResX, PlayResY float64
+	StyleAlign         map[string]int // nama style -> Alignment (numpad)
+	Cues               []Cue
+}
+
+var (
+	reASSTime      = regexp.MustCompile(`(\d+):(\d+):(\d+)\.(\d+)`)
+	reASSOverride  = regexp.MustCompile(`\{[^}]*\}`)
+	reASSAlignTag  = regexp.MustCompile(`\\an([1-9])`)
+	reASSLegacyAln = regexp.MustCompile(`\\a(\d+)`)
+	reASSPosTag    = regexp.MustCompile(`\\pos\(\s*(-?[\d.]+)\s*,\s*(-?[\d.]+)\s*\)`)
+)
+
+// assTimeToMs: "H:MM:SS.cc" -> milidetik
+func assTimeToMs(s string) int {
+	m := reASSTime.FindStringSubmatch(s)
+	if len(m) < 5 {
+		return 0
+	}
+	h, _ := strconv.Atoi(m[1])
+	mi, _ := strconv.Atoi(m[2])
+	sec, _ := strconv.Atoi(m[3])
+	frac := m[4]
+	// fraksi ASS biasanya centisecond, tapi toleran terhadap 1-3 digit
+	for len(frac) < 3 {
+		frac += "0"
+	}
+	ms, _ := strconv.Atoi(frac[:3])
+	return ((h*60+mi)*60+sec)*1000 + ms
+}
+
+// parseASSCues membaca PlayRes, alignment tiap style, dan semua Dialogue.
+func parseASSCues(content string) assDoc {
+	content = normalizeEOL(content)
+	doc := assDoc{
+		PlayResX:   defaultPlayResX,
+		PlayResY:   defaultPlayResY,
+		StyleAlign: map[string]int{},
+	}
+	var styleFormat, eventFormat []string
+	section := ""
+	indexOf := func(fields []string, name string) int {
+		for i, f := range fields {
+			if f == name {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, ln := range strings.Split(content, "\n") {
+		trim := strings.TrimSpace(ln)
+		lower := strings.ToLower(trim)
+		switch {
+		case strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]"):
+			section = lower
+		case strings.HasPrefix(lower, "playresx:"):
+			doc.PlayResX = parseFloatSafe(trim[len("playresx:"):], defaultPlayResX)
+		case strings.HasPrefix(lower, "playresy:"):
+			doc.PlayResY = parseFloatSafe(trim[len("playresy:"):], defaultPlayResY)
+		case strings.HasPrefix(lower, "format:"):
+			fields := strings.Split(trim[len("format:"):], ",")
+			for i := range fields {
+				fields[i] = strings.ToLower(strings.TrimSpace(fields[i]))
+			}
+			if section == "[events]" {
+				eventFormat = fields
+			} else {
+				styleFormat = fields
+			}
+		case strings.HasPrefix(lower, "style:") && len(styleFormat) > 0:
+			parts := splitNPreserveTrailing(trim[len("style:"):], ',', len(styleFormat))
+			ni, ai := indexOf(styleFormat, "name"), indexOf(styleFormat, "alignment")
+			if ni >= 0 && ai >= 0 && ni < len(parts) && ai < len(parts) {
+				doc.StyleAlign[parts[ni]] = int(parseFloatSafe(parts[ai], 2))
+			}
+		case strings.HasPrefix(lower, "dialogue:") && len(eventFormat) > 0:
+			parts := splitNPreserveTrailing(trim[len("dialogue:"):], ',', len(eventFormat))
+			if len(parts) < len(eventFormat) {
+				continue
+			}
+			c := Cue{}
+			if i := indexOf(eventFormat, "start"); i >= 0 {
+				c.Start = assTimeToMs(parts[i])
+			}
+			if i := indexOf(eventFormat, "end"); i >= 0 {
+				c.End = assTimeToMs(parts[i])
+			}
+			if i := indexOf(eventFormat, "style"); i >= 0 {
+				c.Style = parts[i]
+			}
+			if i := indexOf(eventFormat, "text"); i >= 0 {
+				c.Text = parts[i]
+			}
+			doc.Cues = append(doc.Cues, c)
+		}
+	}
+	return doc
+}
+
+// legacyAlignToNumpad: nilai \a lama (1-3 bawah, 5-7 atas, 9-11 tengah) -> numpad \an
+func legacyAlignToNumpad(a int) int {
+	switch {
+	case a >= 1 && a <= 3:
+		return a
+	case a >= 5 && a <= 7:
+		return a + 2
+	case a >= 9 && a <= 11:
+		return a - 5
+	}
+	return 2
+}
+
+// msToVTTTime: milidetik -> "HH:MM:SS.mmm"
+func msToVTTTime(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// assTextToVTT: buang override ASS, kecuali \i \b \u yang jadi tag HTML VTT.
+func assTextToVTT(text string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range reASSOverride.FindAllStringIndex(text, -1) {
+		sb.WriteString(vttEscape(text[last:loc[0]]))
+		block := text[loc[0]:loc[1]]
+		for _, tag := range []string{"i", "b", "u"} {
+			if strings.Contains(block, `\`+tag+"1") {
+				sb.WriteString("<" + tag + ">")
+			}
+			if strings.Contains(block, `\`+tag+"0") {
+				sb.WriteString("</" + tag + ">")
+			}
+		}
+		last = loc[1]
+	}
+	sb.WriteString(vttEscape(text[last:]))
+	out := sb.String()
+	out = strings.ReplaceAll(out, `\N`, "\n")
+	out = strings.ReplaceAll(out, `\n`, "\n")
+	// \h jadi spasi biasa setelah trim, supaya indentasi hard space tetap ada
+	return strings.ReplaceAll(strings.TrimSpace(out), `\h`, " ")
+}
+
+func vttEscape(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	return strings.ReplaceAll(s, ">", "&gt;")
+}
+
+// vttCueSettings membangun cue settings dari alignment (\an / style) dan \pos.
+func vttCueSettings(c Cue, doc assDoc) string {
+	an, ok := doc.StyleAlign[c.Style]
+	if !ok {
+		an = 2
+	}
+	if m := reASSAlignTag.FindStringSubmatch(c.Text); len(m) == 2 {
+		an, _ = strconv.Atoi(m[1])
+	} else if m := reASSLegacyAln.FindStringSubmatch(c.Text); len(m) == 2 {
+		n, _ := strconv.Atoi(m[1])
+		an = legacyAlignToNumpad(n)
+	}
+
+	var settings []string
+	if m := reASSPosTag.FindStringSubmatch(c.Text); len(m) == 3 && doc.PlayResX > 0 && doc.PlayResY > 0 {
+		x := parseFloatSafe(m[1], 0) / doc.PlayResX * 100
+		y := parseFloatSafe(m[2], 0) / doc.PlayResY * 100
+		settings = append(settings, "line:"+formatScaled(y)+"%", "position:"+formatScaled(x)+"%")
+	} else if an >= 7 {
+		settings = append(settings, "line:0")
+	} else if an >= 4 {
+		settings = append(settings, "line:50%")
+	}
+	switch an % 3 {
+	case 1:
+		settings = append(settings, "align:start")
+	case 0:
+		settings = append(settings, "align:end")
+	}
+	return strings.Join(settings, " ")
+}
+
+// writeVTT menulis cue menjadi WebVTT lengkap dengan cue settings posisi.
+func writeVTT(doc assDoc) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for _, c := range doc.Cues {
+		text := assTextToVTT(c.Text)
+		if text == "" {
+			continue
+		}
+		timing := msToVTTTime(c.Start) + " --> " + msToVTTTime(c.End)
+		if st := vttCueSettings(c, doc); st != "" {
+			timing += " " + st
+		}
+		sb.WriteString(timing + "\n" + text + "\n\n")
+	}
+	return sb.String()
+}
+
+// assTextToPlain: teks tanpa override ASS, \N jadi baris baru.
+func assTextToPlain(text string) string {
+	out := reASSOverride.ReplaceAllString(text, "")
+	out = strings.ReplaceAll(out, `\N`, "\n")
+	out = strings.ReplaceAll(out, `\n`, "\n")
+	// \h jadi spasi biasa setelah trim, supaya indentasi hard space tetap ada
+	return strings.ReplaceAll(strings.TrimSpace(out), `\h`, " ")
+}
+
+// stripDrawings membuang koordinat drawing (teks antara \p1..\pN dan \p0);
+// override block dibiarkan untuk dibuang assTextToPlain.
+func stripDrawings(text string) string {
+	var sb strings.Builder
+	drawing := false
+	for text != "" {
+		open := strings.Index(text, "{")
+		end := -1
+		if open >= 0 {
+			end = strings.Index(text[open:], "}")
+		}
+		if open < 0 || end < 0 {
+			open, end = len(text), 0
+		}
+		if !drawing {
+			sb.WriteString(text[:open])
+		}
+		if open == len(text) {
+			break
+		}
+		inner := text[open+1 : open+end]
+		if m := reResDrawLevel.FindAllStringSubmatch(inner, -1); m != nil {
+			drawing = drawLevel(m[len(m)-1][1]) != 0
+		}
+		sb.WriteString("{" + inner + "}")
+		text = text[open+end+1:]
+	}
+	return sb.String()
+}
+
+// writeTranscript: transkrip teks polos, satu baris per cue urut waktu, tanpa
+// tag ASS/HTML dan dengan \N jadi spasi. Koordinat drawing (\p1 dst.) dibuang.
+// withTimestamps menambahkan waktu mulai di depan tiap baris.
+func writeTranscript(doc assDoc, withTimestamps bool) string {
+	cues := append([]Cue(nil), doc.Cues...)
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	var sb strings.Builder
+	prev := ""
+	for _, c := range cues {
+		line := strings.Join(strings.Fields(stripHTMLTags(assTextToPlain(stripDrawings(c.Text)))), " ")
+		if line == "" {
+			continue
+		}
+		if withTimestamps {
+			fmt.Fprintf(&sb, "[%s] %s\n", msToVTTTime(c.Start), line)
+			continue
+		}
+		// baris kembar berurutan (mis. tanda berlapis) cukup sekali
+		if line != prev {
+			sb.WriteString(line + "\n")
+		}
+		prev = line
+	}
+	return sb.String()
+}
+
+// writeCueSheet menulis satu baris per cue (index, start, end, duration, text, style)
+// untuk alur terjemahan di spreadsheet. sep ',' untuk CSV, '\t' untuk TSV.
+func writeCueSheet(doc assDoc, sep rune) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = sep
+	w.Write([]string{"index", "start", "end", "duration", "text", "style"})
+	for i, c := range doc.Cues {
+		w.Write([]string{
+			strconv.Itoa(i + 1),
+			msToVTTTime(c.Start),
+			msToVTTTime(c.End),
+			strconv.FormatFloat(float64(c.End-c.Start)/1000, 'f', 3, 64),
+			assTextToPlain(c.Text),
+			c.Style,
+		})
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// msToASSTime: milidetik → H:MM:SS.cc
+func msToASSTime(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%d:%02d:%02d.%02d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000/10)
+}
+
+// convertCueSheetToASS: kebalikan writeCueSheet. Timing & style diambil dari sheet,
+// teks dari kolom "translation"/"terjemahan" bila terisi, selain itu kolom "text".
+func convertCueSheetToASS(path string) (string, cueReport, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return "", cueReport{}, err
+	}
+	defer f.Close()
+	comma := ','
+	if inputExt(path) == ".tsv" {
+		comma = '\t'
+	}
+	return cueSheetToASS(f, comma)
+}
+
+// convertCueSheetWithTiming: seperti convertCueSheetToASS, tapi timing, style,
+// dan header diambil dari file timing (lihat cueSheetOntoASS).
+func convertCueSheetWithTiming(path, timing string) (string, error) {
+	orig, err := timingSource(timing)
+	if err != nil {
+		return "", err
+	}
+	f, err := openInput(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	comma := ','
+	if inputExt(path) == ".tsv" {
+		comma = '\t'
+	}
+	return cueSheetOntoASS(orig, f, comma)
+}
+
+// cueSheetRow: satu baris cue sheet. Text sudah diambil dari kolom
+// "translation"/"terjemahan" bila terisi, selain itu dari kolom "text".
+type cueSheetRow struct {
+	Index      int // kolom "index" (0 jika tidak ada atau bukan angka)
+	Start, End string
+	Style      string
+	Text       string
+}
+
+// readCueSheet membaca sheet dari in dengan pemisah comma (',' CSV, '\t' TSV);
+// kolom need wajib ada di header.
+func readCueSheet(in io.Reader, comma rune, need ...string) ([]cueSheetRow, error) {
+	r := csv.NewReader(in)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse cue sheet: %v", err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("cue sheet kosong")
+	}
+
+	col := map[string]int{}
+	for i, name := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range need {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("kolom %q tidak ditemukan di cue sheet", name)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	out := make([]cueSheetRow, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		text := get(row, "translation")
+		if text == "" {
+			text = get(row, "terjemahan")
+		}
+		if text == "" {
+			text = get(row, "text")
+		}
+		index, _ := strconv.Atoi(get(row, "index"))
+		out = append(out, cueSheetRow{
+			Index: index,
+			Start: get(row, "start"),
+			End:   get(row, "end"),
+			Style: get(row, "style"),
+			Text:  strings.ReplaceAll(text, "\r\n", "\n"),
+		})
+	}
+	return out, nil
+}
+
+// cueSheetToASS: seperti convertCueSheetToASS, membaca sheet dari in dengan
+// pemisah comma (',' CSV, '\t' TSV).
+func cueSheetToASS(in io.Reader, comma rune) (string, cueReport, error) {
+	var report cueReport
+	rows, err := readCueSheet(in, comma, "start", "end", "text")
+	if err != nil {
+		return "", report, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(cfg.assHeader() + "\n")
+	for _, row := range rows {
+		if row.Text == "" {
+			continue
+		}
+		start, end := assTimeToMs(row.Start), assTimeToMs(row.End)
+		if err := validateCue(start, end); err != nil {
+			report.Dropped++
+			continue
+		}
+		style := row.Style
+		if style == "" {
+			style = "Default"
+		}
+		text := strings.ReplaceAll(row.Text, "\n", assLineBreak())
+		if style == "Default" {
+			text = opts.DefaultFX + text
+		}
+		sb.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,,0000,0000,0000,,%s\n",
+			msToASSTime(start), msToASSTime(end), cfg.outStyle(style), text))
+	}
+	return sb.String(), report, nil
+}
+
+// reLeadingOverrides: override block di awal teks event ({\pos..}{\blur3}...).
+var reLeadingOverrides = regexp.MustCompile(`^(?:\{[^}]*\})*`)
+
+// cueSheetOntoASS: import terjemahan dengan timing asli (-timing). orig adalah
+// ASS yang dulu diekspor; baris ke-N sheet (kolom index, atau urutan baris)
+// mengganti teks Dialogue ke-N. Timing, style, margin, header, dan override di
+// depan teks (posisi, efek) tetap dari orig; kolom start/end/style sheet
+// diabaikan. Baris sheet yang kosong membiarkan teks asli.
+func cueSheetOntoASS(orig string, in io.Reader, comma rune) (string, error) {
+	rows, err := readCueSheet(in, comma, "text")
+	if err != nil {
+		return "", err
+	}
+	texts := map[int]string{}
+	maxIndex := 0
+	for i, row := range rows {
+		idx := row.Index
+		if idx <= 0 {
+			idx = i + 1
+		}
+		texts[idx] = row.Text
+		maxIndex = max(maxIndex, idx)
+	}
+
+	lines := strings.Split(normalizeEOL(orig), "\n")
+	var eventFormat []string
+	section := ""
+	n := 0
+	for i, ln := range lines {
+		trim := strings.TrimSpace(ln)
+		lower := strings.ToLower(trim)
+		switch {
+		case strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]"):
+			section = lower
+		case section == "[events]" && strings.HasPrefix(lower, "format:"):
+			eventFormat = strings.Split(trim[len("format:"):], ",")
+			for k := range eventFormat {
+				eventFormat[k] = strings.ToLower(strings.TrimSpace(eventFormat[k]))
+			}
+		case section == "[events]" && strings.HasPrefix(lower, "dialogue:") && len(eventFormat) > 0:
+			n++
+			if eventFormat[len(eventFormat)-1] != "text" {
+				continue
+			}
+			// Text kolom terakhir: mulai setelah koma ke-(jumlah kolom - 1)
+			at := len("dialogue:")
+			for k := 1; k < len(eventFormat) && at >= 0; k++ {
+				if c := strings.IndexByte(trim[at:], ','); c >= 0 {
+					at += c + 1
+				} else {
+					at = -1
+				}
+			}
+			if at < 0 {
+				continue
+			}
+			old := trim[at:]
+			// teks yang sama dengan hasil ekspor berarti belum diterjemahkan
+			text := texts[n]
+			if text == "" || text == assTextToPlain(old) {
+				continue
+			}
+			lines[i] = trim[:at] + reLeadingOverrides.FindString(old) + strings.ReplaceAll(text, "\n", assLineBreak())
+		}
+	}
+	if maxIndex > n {
+		return "", fmt.Errorf("cue sheet sampai index %d, tapi file -timing hanya punya %d Dialogue", maxIndex, n)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// timingSource: isi ASS file -timing. File non-ASS (mis. SRT yang dulu
+// diekspor ke CSV) dibangun ulang lewat processSRT, sama seperti saat ekspor.
+func timingSource(path string) (string, error) {
+	if inputFormat(path) == ".ass" {
+		data, err := readInput(path)
+		if err != nil {
+			return "", fmt.Errorf("gagal membaca file -timing: %w", err)
+		}
+		return string(data), nil
+	}
+	srt, err := readAsSRT(path)
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca file -timing: %w", err)
+	}
+	return processSRT([]byte(prepareSRT(srt))), nil
+}
+
+// ======================================
+// 🔹 JSON parsers & detection (Bilibili & YouTube)
+// ======================================
+
+// Bili JSON structure (common)
+type biliBodyEntry struct {
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Location int     `json:"location,omitempty"`
+	Content  string  `json:"content"`
+}
+type biliJSON struct {
+	Body []biliBodyEntry `json:"body"`
+}
+
+// biliLocationTag: location Bilibili memakai tata letak numpad (2 = bawah tengah,
+// default; 8 = atas tengah). Selain default dikembalikan sebagai {\anN}, sehingga
+// processSRT menaruh caption atas di style "Default Above".
+func biliLocationTag(loc int) string {
+	if loc < 1 || loc > 9 || loc == 2 {
+		return ""
+	}
+	return fmt.Sprintf(`{\an%d}`, loc)
+}
+
+// YouTube JSON structure (common shape)
+type ytSeg struct {
+	UTF8      string  `json:"utf8"`
+	TOffsetMs float64 `json:"tOffsetMs"` // offset kata dari tStartMs event (auto-caption)
+}
+type ytEvent struct {
+	TStartMs   float64 `json:"tStartMs"`   // can be integer or float in JSON -> use float64
+	DDurationMs float64 `json:"dDurationMs"` // duration in ms
+	Segs       []ytSeg `json:"segs"`
+}
+type ytJSON struct {
+	Events []ytEvent `json:"events"`
+}
+
+// Array datar dari scraper: [{"start":1.2,"end":3.4,"text":"hi"}], detik.
+// Varian startTime/endTime juga diterima.
+type flatJSONCue struct {
+	Start     *float64 `json:"start"`
+	End       *float64 `json:"end"`
+	StartTime *float64 `json:"startTime"`
+	EndTime   *float64 `json:"endTime"`
+	Text      string   `json:"text"`
+}
+
+// flatJSONToSRT: "" jika data bukan array cue datar yang valid.
+func flatJSONToSRT(data []byte) string {
+	var items []flatJSONCue
+	if err := json.Unmarshal(data, &items); err != nil {
+		return ""
+	}
+	pick := func(a, b *float64) (float64, bool) {
+		if a != nil {
+			return *a, true
+		}
+		if b != nil {
+			return *b, true
+		}
+		return 0, false
+	}
+	var sb strings.Builder
+	counter := 1
+	for _, it := range items {
+		start, ok1 := pick(it.Start, it.StartTime)
+		end, ok2 := pick(it.End, it.EndTime)
+		text := strings.TrimSpace(it.Text)
+		if !ok1 || !ok2 || text == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter, formatTime(start), formatTime(end), text))
+		counter++
+	}
+	return sb.String()
+}
+
+// ytEventText: teks satu event YouTube. Default level kalimat (segmen digabung);
+// dengan -yt-karaoke tiap segmen diberi \k sesuai tStartMs + tOffsetMs kata berikutnya.
+func ytEventText(ev ytEvent) string {
+	if !opts.YTKaraoke || len(ev.Segs) < 2 {
+		// segmen auto-caption membawa spasinya sendiri (" kata"), jadi digabung
+		// mentah lalu spasi tiap baris dirapikan; baris kosong dibuang
+		var raw strings.Builder
+		for _, s := range ev.Segs {
+			raw.WriteString(s.UTF8)
+		}
+		var lines []string
+		for _, l := range strings.Split(raw.String(), "\n") {
+			if l = strings.Join(strings.Fields(l), " "); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	var sb strings.Builder
+	for i, s := range ev.Segs {
+		end := ev.DDurationMs
+		if i+1 < len(ev.Segs) {
+			end = ev.Segs[i+1].TOffsetMs
+		}
+		cs := int((end-s.TOffsetMs)/10 + 0.5)
+		if cs < 0 {
+			cs = 0
+		}
+		sb.WriteString(fmt.Sprintf(`{\k%d}`, cs) + s.UTF8)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// convertJSONtoSRT: baca file .json, deteksi format, kembalikan string SRT
+func convertJSONtoSRT(path string) (string, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return "", err
+	}
+	return jsonToSRT(data)
+}
+
+// jsonToSRT: isi JSON (Bilibili, YouTube, atau array datar) → SRT, tanpa membaca file.
+func jsonToSRT(data []byte) (string, error) {
+	// Deteksi struktural: kunci top-level & tipenya, bukan cari substring di teks
+	// (teks caption yang berisi kata "body"/"events" tidak boleh salah deteksi)
+	format, err := detectJSONFormat(data)
+	if err != nil {
+		return "", fmt.Errorf("format JSON tidak dikenali dan gagal decode: %v", err)
+	}
+
+	switch format {
+	case "bilibili":
+		var b biliJSON
+		if err := json.Unmarshal(data, &b); err != nil {
+			return "", fmt.Errorf("gagal parse JSON Bilibili: %v", err)
+		}
+		srt := biliBodyToSRT(b.Body)
+		if srt == "" {
+			return "", fmt.Errorf("tidak ada caption valid ditemukan di Bilibili JSON")
+		}
+		return srt, nil
+
+	case "youtube":
+		var y ytJSON
+		if err := json.Unmarshal(data, &y); err != nil {
+			return "", fmt.Errorf("gagal parse JSON YouTube: %v", err)
+		}
+		srt := ytEventsToSRT(y.Events)
+		if srt == "" {
+			return "", fmt.Errorf("tidak ada caption valid ditemukan di YouTube JSON")
+		}
+		return srt, nil
+
+	case "flat":
+		// Array datar {start,end,text} (bukan Bilibili/YouTube)
+		if srt := flatJSONToSRT(data); srt != "" {
+			return srt, nil
+		}
+		return "", fmt.Errorf("format JSON array tidak dikenali atau tidak ada caption")
+	}
+
+	return "", fmt.Errorf("format JSON tidak dikenali atau tidak ada caption")
+}
+
+// detectJSONFormat: "bilibili" (objek dengan array body), "youtube" (objek dengan
+// array events), "flat" (array top-level), atau "" jika tidak dikenali.
+// Nama kunci dicocokkan tanpa peduli huruf besar/kecil, sama seperti encoding/json.
+// Jika keduanya ada, body yang menang.
+func detectJSONFormat(data []byte) (string, error) {
+	var probe interface{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", err
+	}
+	switch v := probe.(type) {
+	case []interface{}:
+		return "flat", nil
+	case map[string]interface{}:
+		// urutan map Go acak: kumpulkan dulu kunci (case-insensitive) yang
+		// berisi array, lalu cek body sebelum events
+		arrays := map[string]bool{}
+		for k, val := range v {
+			if _, isArray := val.([]interface{}); isArray {
+				arrays[strings.ToLower(k)] = true
+			}
+		}
+		switch {
+		case arrays["body"]:
+			return "bilibili", nil
+		case arrays["events"]:
+			return "youtube", nil
+		}
+	}
+	return "", nil
+}
+
+// biliBodyToSRT: "" jika tidak ada entry valid.
+func biliBodyToSRT(body []biliBodyEntry) string {
+	var sb strings.Builder
+	counter := 1
+	for _, it := range body {
+		// durasi nol/negatif dibuang (dan dihitung) oleh validateCue di processSRT
+		start := it.From
+		end := it.To
+		content := strings.TrimSpace(it.Content)
+		// caption yang kosong setelah tag dibuang dilewati, supaya tidak jadi
+		// cue berisi {\anN} saja
+		if assTextToPlain(stripHTMLTags(content)) == "" {
+			continue
+		}
+		content = biliLocationTag(it.Location) + content
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", counter, formatTime(start), formatTime(end), content))
+		counter++
+	}
+	return sb.String()
+}
+
+var reSRTTimingLine = regexp.MustCompile(`^(\s*)(\d+):(\d+):(\d+),(\d+)(\s*-->\s*)(\d+):(\d+):(\d+),(\d+)(.*)$`)
+
+// prepareSRT menjalankan pass waktu (-fix-times, -sync/-retime, -min-display)
+// atas SRT perantara sebelum processSRT, jadi berlaku untuk semua format input.
+func prepareSRT(srt string) string {
+	srt = normalizeEOL(srt)
+	if opts.FixTimes {
+		srt = fixSwappedTimes(srt)
+	}
+	if opts.TimeScale != 0 {
+		srt = remapTimes(srt, opts.TimeScale, opts.TimeOffset)
+	}
+	if opts.MinDisplay > 0 {
+		srt = extendShortCues(srt, int(opts.MinDisplay/time.Millisecond))
+	}
+	return srt
+}
+
+// syncPoint: satu titik acuan -sync, waktu sumber → waktu target (ms).
+type syncPoint struct {
+	From, To int
+}
+
+// parseSyncPoint: "00:00:10,000=00:00:10,400" → syncPoint.
+func parseSyncPoint(s string) (syncPoint, error) {
+	invalid := fmt.Errorf("nilai -sync %q tidak valid, contoh: 00:00:10,000=00:00:10,400", s)
+	from, to, ok := strings.Cut(s, "=")
+	if !ok {
+		return syncPoint{}, invalid
+	}
+	f, err := parseShift(from)
+	if err != nil {
+		return syncPoint{}, invalid
+	}
+	t, err := parseShift(to)
+	if err != nil {
+		return syncPoint{}, invalid
+	}
+	return syncPoint{f, t}, nil
+}
+
+// syncRemap menghitung skala & offset dari titik -sync. Satu titik = geser
+// saja; dua titik = remap linear yang tepat di kedua titik (mis. drift
+// 23.976↔25 fps).
+func syncRemap(points []syncPoint) (scale, offset float64, err error) {
+	switch len(points) {
+	case 1:
+		return 1, float64(points[0].To - points[0].From), nil
+	case 2:
+		a, b := points[0], points[1]
+		if a.From == b.From {
+			return 0, 0, fmt.Errorf("kedua titik -sync punya waktu sumber yang sama")
+		}
+		scale = float64(b.To-a.To) / float64(b.From-a.From)
+		if scale <= 0 {
+			return 0, 0, fmt.Errorf("titik -sync membalik urutan waktu")
+		}
+		return scale, float64(a.To) - float64(a.From)*scale, nil
+	}
+	return 0, 0, fmt.Errorf("-sync butuh 1 atau 2 titik, diberikan %d", len(points))
+}
+
+// retimePresets: konversi fps yang umum, SRC → DST.
+var retimePresets = map[string][2]string{
+	"25to23.976": {"25", "23.976"},
+	"23.976to25": {"23.976", "25"},
+	"24to25":     {"24", "25"},
+	"30to29.97":  {"30", "29.97"},
+}
+
+// parseFPS: angka fps, dengan 23.976/29.97/59.94 dibaca sebagai nilai NTSC
+// persisnya (24000/1001 dst.) supaya drift panjang tetap tepat.
+func parseFPS(s string) (float64, error) {
+	switch strings.TrimSpace(s) {
+	case "23.976", "23.98":
+		return 24000.0 / 1001, nil
+	case "29.97":
+		return 30000.0 / 1001, nil
+	case "59.94":
+		return 60000.0 / 1001, nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("fps %q tidak valid", s)
+	}
+	return v, nil
+}
+
+// parseRetime (-retime): preset atau "SRC:DST" → skala waktu SRC/DST.
+// Subtitle untuk 25 fps yang diputar di video 23.976 jadi lebih panjang.
+func parseRetime(s string) (float64, error) {
+	pair, ok := retimePresets[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		src, dst, found := strings.Cut(s, ":")
+		if !found {
+			return 0, fmt.Errorf("nilai -retime %q tidak valid, gunakan 25to23.976, 23.976to25, 24to25, 30to29.97 atau SRC:DST", s)
+		}
+		pair = [2]string{src, dst}
+	}
+	src, err := parseFPS(pair[0])
+	if err != nil {
+		return 0, fmt.Errorf("nilai -retime %q: %w", s, err)
+	}
+	dst, err := parseFPS(pair[1])
+	if err != nil {
+		return 0, fmt.Errorf("nilai -retime %q: %w", s, err)
+	}
+	return src / dst, nil
+}
+
+// remapTimes menerapkan t' = t*scale + offsetMs ke semua baris timing SRT,
+// dibulatkan ke milidetik terdekat.
+func remapTimes(srt string, scale, offsetMs float64) string {
+	remap := func(g []string) string {
+		ms := float64(srtTimeToMs(strings.Join(g[0:3], ":") + "," + g[3]))
+		return formatTime(math.Round(ms*scale+offsetMs) / 1000)
+	}
+	lines := strings.Split(srt, "\n")
+	for i, ln := range lines {
+		m := reSRTTimingLine.FindStringSubmatch(strings.TrimRight(ln, "\r"))
+		if m == nil {
+			continue
+		}
+		lines[i] = m[1] + remap(m[2:6]) + m[6] + remap(m[7:11]) + m[11]
+		if strings.HasSuffix(ln, "\r") {
+			lines[i] += "\r"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// minDisplayGapMs: jeda yang disisakan -min-display sebelum cue berikutnya
+// (±2 frame di 25 fps), supaya renderer tidak menampilkan keduanya bersamaan.
+const minDisplayGapMs = 80
+
+// extendShortCues (-min-display): cue yang lebih pendek dari minMs
+// diperpanjang sampai minMs, tapi tidak melewati start cue berikutnya dikurangi
+// minDisplayGapMs. Cue yang sudah cukup panjang tidak disentuh.
+func extendShortCues(srt string, minMs int) string {
+	lines := strings.Split(srt, "\n")
+	type timing struct {
+		line       int
+		start, end int
+		m          []string
+	}
+	var cues []timing
+	for i, ln := range lines {
+		m := reSRTTimingLine.FindStringSubmatch(strings.TrimRight(ln, "\r"))
+		if m == nil {
+			continue
+		}
+		cues = append(cues, timing{i,
+			srtTimeToMs(strings.Join(m[2:5], ":") + "," + m[5]),
+			srtTimeToMs(strings.Join(m[7:10], ":") + "," + m[10]), m})
+	}
+	// input belum tentu urut: start diurutkan sekali, cue berikutnya dicari
+	// dengan binary search (start terdekat yang lebih besar dari cue ini)
+	starts := make([]int, len(cues))
+	for i, c := range cues {
+		starts[i] = c.start
+	}
+	sort.Ints(starts)
+	for _, c := range cues {
+		if c.end-c.start >= minMs {
+			continue
+		}
+		end := c.start + minMs
+		if k := sort.SearchInts(starts, c.start+1); k < len(starts) && starts[k]-minDisplayGapMs < end {
+			end = starts[k] - minDisplayGapMs
+		}
+		if end <= c.end {
+			continue
+		}
+		verbosef("min-display: baris %d diperpanjang %d ms → %d ms", c.line+1, c.end-c.start, end-c.start)
+		ln := c.m[1] + formatTime(float64(c.start)/1000) + c.m[6] + formatTime(float64(end)/1000) + c.m[11]
+		if strings.HasSuffix(lines[c.line], "\r") {
+			ln += "\r"
+		}
+		lines[c.line] = ln
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fixSwappedTimes (-fix-times): baris timing SRT yang start-nya lebih besar
+// dari end ditukar balik, dan dicatat ke stderr. Dipanggil untuk semua format
+// sebelum masuk processSRT, jadi cue-nya tidak dibuang validateCue.
+func fixSwappedTimes(srt string) string {
+	lines := strings.Split(srt, "\n")
+	for i, ln := range lines {
+		m := reSRTTimingLine.FindStringSubmatch(strings.TrimRight(ln, "\r"))
+		if m == nil {
+			continue
+		}
+		ms := func(g []string) int {
+			h, _ := strconv.Atoi(g[0])
+			mi, _ := strconv.Atoi(g[1])
+			se, _ := strconv.Atoi(g[2])
+			f, _ := strconv.Atoi(g[3])
+			return ((h*60+mi)*60+se)*1000 + f
+		}
+		if ms(m[2:6]) <= ms(m[7:11]) {
+			continue
+		}
+		start := strings.Join(m[2:5], ":") + "," + m[5]
+		end := strings.Join(m[7:10], ":") + "," + m[10]
+		fmt.Fprintf(os.Stderr, "peringatan: baris %d: start %s > end %s, waktunya ditukar\n", i+1, start, end)
+		lines[i] = m[1] + end + m[6] + start + m[11]
+		if strings.HasSuffix(ln, "\r") {
+			lines[i] += "\r"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinSRT menyambung beberapa SRT jadi satu timeline. File ke-i (i ≥ 1)
+// digeser shifts[i-1] ms; bila tidak ada (atau < 0), digeser ke akhir cue
+// terakhir file sebelumnya. Nomor cue diurutkan ulang dari 1.
+func joinSRT(parts []string, shifts []int) string {
+	var sb strings.Builder
+	counter, lastEnd := 1, 0
+	for i, part := range parts {
+		offset := 0
+		if i > 0 {
+			offset = lastEnd
+			if i-1 < len(shifts) && shifts[i-1] >= 0 {
+				offset = shifts[i-1]
+			}
+		}
+		lines := strings.Split(normalizeEOL(strings.TrimPrefix(part, "\ufeff")), "\n")
+		for j, ln := range lines {
+			m := reSRTTimingLine.FindStringSubmatch(ln)
+			if m == nil {
+				// nomor cue = baris angka tepat sebelum baris timing
+				if reCueIndex.MatchString(ln) && j+1 < len(lines) && reSRTTimingLine.MatchString(lines[j+1]) {
+					ln = strconv.Itoa(counter)
+				}
+				sb.WriteString(ln + "\n")
+				continue
+			}
+			start := srtTimeToMs(strings.Join(m[2:5], ":")+","+m[5]) + offset
+			end := srtTimeToMs(strings.Join(m[7:10], ":")+","+m[10]) + offset
+			if end > lastEnd {
+				lastEnd = end
+			}
+			sb.WriteString(m[1] + formatTime(float64(start)/1000) + m[6] + formatTime(float64(end)/1000) + m[11] + "\n")
+			counter++
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// splitSRT memotong SRT di cutMs: cue sebelum titik potong masuk before,
+// sisanya masuk after dengan waktu dikurangi cutMs. Cue yang melintasi titik
+// potong diduplikasi ke keduanya dengan waktu dijepit ke titik potong.
+func splitSRT(srt string, cutMs int) (before, after string) {
+	var b, a strings.Builder
+	nb, na := 1, 1
+	write := func(sb *strings.Builder, n *int, start, end int, text []string) {
+		fmt.Fprintf(sb, "%d\n%s --> %s\n%s\n\n", *n, formatTime(float64(start)/1000), formatTime(float64(end)/1000), strings.Join(text, "\n"))
+		*n++
+	}
+	lines := strings.Split(normalizeEOL(strings.TrimPrefix(srt, "\ufeff")), "\n")
+	for i := 0; i < len(lines); i++ {
+		m := reSRTTimingLine.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		start := srtTimeToMs(strings.Join(m[2:5], ":") + "," + m[5])
+		end := srtTimeToMs(strings.Join(m[7:10], ":") + "," + m[10])
+		var text []string
+		for i+1 < len(lines) && strings.Trim(lines[i+1], " \t\r") != "" {
+			i++
+			text = append(text, lines[i])
+		}
+		if start < cutMs {
+			write(&b, &nb, start, min(end, cutMs), text)
+		}
+		if end > cutMs {
+			write(&a, &na, max(start, cutMs)-cutMs, end-cutMs, text)
+		}
+	}
+	return b.String(), a.String()
+}
+
+// parseShift: "00:45:30,000" (atau titik sebagai pemisah ms) maupun durasi Go
+// seperti "45m30s" → milidetik.
+func parseShift(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return int(d / time.Millisecond), nil
+	}
+	if t := strings.Replace(s, ".", ",", 1); t != "" && reSRTTime.FindString(t) == t {
+		return srtTimeToMs(t), nil
+	}
+	return 0, fmt.Errorf("nilai -shift %q tidak valid, contoh: 00:45:30,000", s)
+}
+
+// formatTime: seconds (float) -> SRT timestamp (HH:MM:SS,mmm)
+func formatTime(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMs := int(seconds*1000 + 0.5)
+	h := totalMs / 3600000
+	totalMs %= 3600000
+	m := totalMs / 60000
+	totalMs %= 60000
+	s := totalMs / 1000
+	ms := totalMs % 1000
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+func safeDialogMessage(title, msg string, isError bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			// fallback ke terminal jika library dialog gagal
+			if isError {
 				fmt.Fprintf(os.Stderr, "\n[%s] %s\n", title, msg)
 			} else {
 				fmt.Printf("\n[%s] %s\n", title, msg)
 			}
 		}
-	}()
+	}()
+
+	if isError {
+		dialog.Message(msg).Title(title).Error()
+	} else {
+		dialog.Message(msg).Title(title).Info()
+	}
+}
+
+
+// ======================================
+// 🔹 Konfigurasi opsional (limesub.json)
+// ======================================
+
+const configFileName = "limesub.json"
+
+// Config berisi default pribadi dari limesub.json. Urutan prioritas:
+// flag CLI > limesub.json > bawaan program. Field kosong = pakai bawaan.
+//
+//	{
+//	  "font": "Basic Comical NC",
+//	  "resolution": "1920x1080",
+//	  "styles": {"tanda": "Style: tanda,Arial,75,..."},
+//	  "style_names": {"tanda": "Sign", "song": "OP-ED"},
+//	  "header_file": "header-grup.ass",
+//	  "flags": {"no-tanda": "true", "merge-gap": "100ms"}
+//	}
+type Config struct {
+	Font       string            `json:"font"`        // font style Default/tanda/res dan target resample
+	Resolution string            `json:"resolution"`  // resolusi target resample ASS, mis. "1920x1080"
+	Styles     map[string]string `json:"styles"`      // nama style → baris "Style: ..." pengganti/tambahan
+	Flags      map[string]string `json:"flags"`       // default flag subcommand, nama tanpa "-"
+	HeaderFile string            `json:"header_file"` // template header ASS (default -header-file), relatif ke limesub.json
+	StyleNames map[string]string `json:"style_names"` // nama style bawaan (Default, Default Above, tanda, song) → nama di output
+}
+
+var (
+	cfg     Config
+	cfgPath string // file yang dimuat, kosong jika tidak ada
+)
+
+// loadConfig mencari limesub.json di direktori kerja, lalu di samping
+// executable. Tidak ada file bukan error; file rusak adalah error.
+func loadConfig() (Config, string, error) {
+	dirs := []string{"."}
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Dir(exe))
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, configFileName)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Config{}, path, fmt.Errorf("gagal membaca %s: %w", path, err)
+		}
+		c, err := parseConfig(data)
+		if err != nil {
+			return Config{}, path, fmt.Errorf("%s: %w", path, err)
+		}
+		if c.HeaderFile != "" && !filepath.IsAbs(c.HeaderFile) {
+			c.HeaderFile = filepath.Join(dir, c.HeaderFile)
+		}
+		return c, path, nil
+	}
+	return Config{}, "", nil
+}
+
+// parseConfig memvalidasi isi limesub.json.
+func parseConfig(data []byte) (Config, error) {
+	var c Config
+	dec := json.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return Config{}, fmt.Errorf("format JSON tidak valid: %w", err)
+	}
+	if c.Resolution != "" {
+		if _, _, err := parseResolution(c.Resolution); err != nil {
+			return Config{}, err
+		}
+	}
+	for name, line := range c.Styles {
+		if !strings.HasPrefix(line, "Style:") {
+			line = "Style: " + line
+		}
+		if styleName(line) != name {
+			return Config{}, fmt.Errorf("style %q: nama di baris Style tidak sama (%q)", name, styleName(line))
+		}
+		c.Styles[name] = line
+	}
+	for name, out := range c.StyleNames {
+		if strings.ContainsAny(out, ",\n") {
+			return Config{}, fmt.Errorf("style_names %q: nama %q tidak boleh berisi koma", name, out)
+		}
+	}
+	return c, nil
+}
+
+// styleName: "Style: tanda,Arial,..." → "tanda"
+func styleName(line string) string {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "Style:")), ",", 2)
+	return strings.TrimSpace(fields[0])
+}
+
+// withStyleFont mengganti kolom Fontname (kolom kedua) di baris Style.
+func withStyleFont(line, font string) string {
+	fields := strings.Split(line, ",")
+	if font == "" || len(fields) < 2 {
+		return line
+	}
+	fields[1] = font
+	return strings.Join(fields, ",")
+}
+
+// outStyle: nama style di output untuk style bawaan name (lihat "style_names").
+func (c Config) outStyle(name string) string {
+	if out, ok := c.StyleNames[name]; ok && out != "" {
+		return out
+	}
+	return name
+}
+
+func (c Config) fontName() string {
+	if c.Font != "" {
+		return c.Font
+	}
+	return targetFontName
+}
+
+func (c Config) resolution() string {
+	if c.Resolution != "" {
+		return c.Resolution
+	}
+	return fmt.Sprintf("%dx%d", int(targetPlayResX), int(targetPlayResY))
+}
+
+// style mengembalikan baris Style untuk name: dari "styles" jika ada,
+// selain itu def dengan font dari "font".
+func (c Config) style(name, def string) string {
+	if line, ok := c.Styles[name]; ok {
+		return line
+	}
+	return withStyleFont(def, c.Font)
+}
+
+// assHeader adalah assHeaderTemplate dengan font dan style dari limesub.json.
+// Style yang tidak ada di template ditambahkan setelah baris Style: terakhir
+// di [V4+ Styles], saat section itu ditutup oleh header berikutnya atau EOF.
+func (c Config) assHeader() string {
+	if c.Font == "" && len(c.Styles) == 0 && len(c.StyleNames) == 0 {
+		return assHeaderTemplate
+	}
+	seen := map[string]bool{}
+	var out []string
+	inStyles := false
+	lastStyle := -1 // indeks di out: tempat style tambahan disisipkan
+	// flushStyles menyisipkan style tambahan (urut nama) saat keluar dari
+	// [V4+ Styles]; tanpa baris Style: sama sekali, disisipkan di akhir section
+	flushStyles := func() {
+		if !inStyles {
+			return
+		}
+		inStyles = false
+		var names []string
+		for name := range c.Styles {
+			if !seen[name] {
+				names = append(names, name)
+				seen[name] = true
+			}
+		}
+		sort.Strings(names)
+		extra := make([]string, len(names))
+		for i, name := range names {
+			extra[i] = c.Styles[name]
+		}
+		at := lastStyle + 1
+		if lastStyle < 0 {
+			at = len(out)
+			for at > 0 && strings.TrimSpace(out[at-1]) == "" {
+				at--
+			}
+		}
+		out = append(out[:at], append(extra, out[at:]...)...)
+	}
+	for _, line := range strings.Split(assHeaderTemplate, "\n") {
+		trim := strings.TrimSpace(line)
+		if strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]") {
+			flushStyles()
+			lower := strings.ToLower(trim)
+			inStyles = lower == "[v4+ styles]" || lower == "[v4 styles]"
+			lastStyle = -1
+		}
+		if strings.HasPrefix(line, "Style:") {
+			name := styleName(line)
+			seen[name] = true
+			line = c.style(name, line)
+			if out, k := c.outStyle(name), strings.Index(line, ","); out != name && k >= 0 {
+				line = "Style: " + out + line[k:]
+			}
+			if inStyles {
+				lastStyle = len(out)
+			}
+		}
+		out = append(out, line)
+	}
+	flushStyles()
+	return strings.Join(out, "\n")
+}
+
+// applyFlags mengisi default flag dari "flags" sebelum fs.Parse, jadi flag
+// dari command line tetap menang. Nama yang bukan milik subcommand ini
+// dilewati (satu file dipakai bersama convert dan resample).
+func (c Config) applyFlags(fs *flag.FlagSet) error {
+	names := make([]string, 0, len(c.Flags))
+	for name := range c.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			verbosef("config: flag -%s tidak berlaku untuk %s, dilewati", name, fs.Name())
+			continue
+		}
+		if err := fs.Set(name, c.Flags[name]); err != nil {
+			return fmt.Errorf("%s: flag -%s: %w", configFileName, name, err)
+		}
+	}
+	return nil
+}
+
+// ======================================
+// Entry point utama
+// ======================================
+func main() {
+	defer func() {
+		if r := recover(); r != nil {
+			safeDialogMessage("Limesub v3 - Error",
+				fmt.Sprintf("Terjadi kesalahan tak terduga:\n\n%v", r),
+				true)
+		}
+	}()
+
+	c, path, err := loadConfig()
+	if err != nil {
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Gagal memuat konfigurasi:\n\n%v", err),
+			true)
+		return
+	}
+	cfg, cfgPath = c, path
+
+	// Subcommand: convert (default), resample, inspect, join, split. Argumen pertama yang
+	// bukan nama subcommand (mis. file hasil drag & drop) berarti convert.
+	cmd, args := "convert", os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "convert", "resample", "inspect", "join", "split":
+			cmd, args = args[0], args[1:]
+		}
+	}
+	switch cmd {
+	case "resample":
+		runResample(args)
+	case "join":
+		runJoin(args)
+	case "split":
+		runSplit(args)
+	case "inspect":
+		runInspect(args)
+	default:
+		runConvert(args)
+	}
+}
+
+// runConvert: format subtitle apa pun → ASS limenime (perilaku drag & drop).
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.BoolVar(&opts.DetectSign, "detect-sign", false,
+		"deteksi tanda memakai durasi, posisi, dan tanda baca (bukan hanya huruf kapital)")
+	fs.BoolVar(&opts.NoTanda, "no-tanda", false,
+		"jangan pernah memakai style tanda (semua baris jadi dialog biasa)")
+	fs.BoolVar(&opts.GroupTanda, "group-tanda", false,
+		"kumpulkan semua baris tanda di awal [Events] seperti versi lama (default: urut waktu)")
+	fs.BoolVar(&opts.Check, "check", false,
+		"periksa file .ass (mis. \\fs yang menyimpang jauh dari style) tanpa menulis output")
+	fs.Func("sign-weights", "bobot detektor tanda, mis. caps=1,bracket=2,pos=2,short=0.5,nopunct=0.5,shortms=1500,threshold=2",
+		func(s string) error {
+			w, err := parseSignWeights(s)
+			if err != nil {
+				return err
+			}
+			opts.SignWeights = w
+			return nil
+		})
+	fs.StringVar(&opts.To, "to", opts.To, "format output: ass, vtt, csv, tsv, txt (transkrip) atau srt (rapikan SRT)")
+	fs.BoolVar(&opts.WithTimestamps, "with-timestamps", false, "-to txt: awali tiap baris dengan waktu mulai cue")
+	fs.Float64Var(&opts.FPS, "fps", opts.FPS, "fps untuk timecode berbasis frame bila file tidak mencantumkannya (default 25)")
+	fs.BoolVar(&opts.FPSDetect, "fps-detect", opts.FPSDetect, "pakai fps dari metadata file (TTML ttp:frameRate) bila ada")
+	fs.DurationVar(&opts.MergeGap, "merge-gap", 0, "gabungkan cue dengan teks & style sama yang jedanya di bawah nilai ini, mis. 100ms")
+	fs.BoolVar(&opts.YTCoalesce, "yt-coalesce", false, "JSON YouTube: gabungkan rolling caption yang waktunya tumpang tindih jadi satu cue")
+	fs.BoolVar(&opts.YTKaraoke, "yt-karaoke", false, "JSON YouTube: pakai timing per kata (tOffsetMs) sebagai tag karaoke \\k")
+	fs.BoolVar(&opts.Annotate, "annotate", false, "tulis baris Comment: berisi nomor dan timing cue sumber sebelum tiap Dialogue")
+	fs.BoolVar(&opts.FixTimes, "fix-times", false, "tukar balik cue yang start-nya lebih besar dari end (default: cue dibuang)")
+	fs.DurationVar(&opts.MinDuration, "min-duration", 0, "buang cue yang durasinya di bawah nilai ini, mis. 500ms")
+	fs.Func("sync", "titik acuan remap waktu SUMBER=TARGET, mis. 00:00:10,000=00:00:10,400 (ulangi 2x untuk skala + offset)",
+		func(s string) error {
+			p, err := parseSyncPoint(s)
+			if err != nil {
+				return err
+			}
+			opts.Sync = append(opts.Sync, p)
+			return nil
+		})
+	retime := fs.String("retime", "", "konversi fps: 25to23.976, 23.976to25, 24to25, 30to29.97, atau SRC:DST")
+	fs.DurationVar(&opts.MinDisplay, "min-display", 0, "perpanjang cue yang tampil di bawah nilai ini, mis. 1s (tidak melewati cue berikutnya)")
+	fs.BoolVar(&opts.Strict, "strict", false, "buang cue yang baris timing-nya tidak valid (default: tetap ditulis dengan waktu nol)")
+	fs.BoolVar(&opts.PreserveSpacing, "preserve-spacing", false, "pertahankan spasi beruntun dan indentasi di teks (spasi awal jadi \\h)")
+	fs.StringVar(&opts.TimeUnit, "time-unit", opts.TimeUnit, "satuan waktu <st>/<et> Custom XML: auto, cs, ms, atau s")
+	fs.BoolVar(&opts.Benchmark, "benchmark", false, "cetak waktu yang dihabiskan di fase parse, transform, dan serialize")
+	fs.BoolVar(&opts.Verbose, "v", false, "cetak langkah yang dilakukan ke stderr")
+	fs.StringVar(&opts.DefaultFX, "default-fx", opts.DefaultFX, "override yang ditaruh di awal tiap baris style Default")
+	fs.IntVar(&opts.Track, "track", 0, "MKV: nomor track subtitle yang diambil (default: track ASS/SRT pertama)")
+	fs.StringVar(&opts.Timing, "timing", "", "import CSV/TSV: file asal (mis. .ass yang diekspor) yang timing, style & posisinya dipakai; teks diganti terjemahan")
+	fs.StringVar(&opts.Lang, "lang", "", "TTML multibahasa: ambil hanya xml:lang ini, mis. en (default: bahasa pertama)")
+	fs.StringVar(&opts.LineBreak, "linebreak", opts.LineBreak, "pemisah baris yang digabung: hard (\\N) atau soft (\\n, putus hanya bila perlu)")
+	fs.BoolVar(&opts.RTL, "rtl", false, "perlakukan semua cue sebagai teks kanan-ke-kiri (default: dideteksi dari aksara Arab/Ibrani)")
+	fs.BoolVar(&opts.SRTCoords, "srt-coords", false, "pakai koordinat SRT (X1:.. X2:.. Y1:.. Y2:..) sebagai \\pos pojok kiri atas (default: diabaikan)")
+	fs.BoolVar(&opts.Flatten, "flatten", false, "buang \\pos/\\move/\\org/\\an/\\clip dan paksa style Default (semua baris di bawah tengah)")
+	fs.BoolVar(&opts.RTLq2, "rtl-q2", false, "tambahkan {\\q2} di cue RTL supaya renderer tidak memutus baris")
+	jobs := fs.Int("j", 1, "jumlah file yang dikonversi bersamaan saat input lebih dari satu")
+	noDefaultFX := fs.Bool("no-default-fx", false, "jangan tambahkan efek apa pun ke baris style Default")
+	headerFile := fs.String("header-file", cfg.HeaderFile, "template header ASS (Script Info + styles) pengganti header limenime")
+	resolveLineEndings := lineEndingFlags(fs)
+	if err := cfg.applyFlags(fs); err != nil {
+		safeDialogMessage("Limesub v3 - Error", err.Error(), true)
+		return
+	}
+	fs.Parse(args)
+	if cfgPath != "" {
+		verbosef("config: memakai %s", cfgPath)
+	}
+	if *noDefaultFX {
+		opts.DefaultFX = ""
+	}
+	if *headerFile != "" {
+		header, err := loadHeaderTemplate(*headerFile)
+		if err != nil {
+			safeDialogMessage("Limesub v3 - Error", err.Error(), true)
+			return
+		}
+		assHeaderTemplate = header
+		verbosef("convert: header dari %s", *headerFile)
+	}
+
+	switch opts.To {
+	case "ass", "vtt", "csv", "tsv", "txt", "srt":
+	default:
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Format output -to %q tidak didukung.\n\nGunakan ass, vtt, csv, tsv, txt atau srt.", opts.To),
+			true)
+		return
+	}
+	resolveLineEndings(opts.To)
+	if len(opts.Sync) > 0 {
+		scale, offset, err := syncRemap(opts.Sync)
+		if err != nil {
+			safeDialogMessage("Limesub v3 - Error", err.Error(), true)
+			return
+		}
+		opts.TimeScale, opts.TimeOffset = scale, offset
+		verbosef("sync: t' = t × %.6f %+.0f ms", scale, offset)
+	}
+	if *retime != "" {
+		if len(opts.Sync) > 0 {
+			safeDialogMessage("Limesub v3 - Error", "-retime dan -sync tidak bisa dipakai bersamaan.", true)
+			return
+		}
+		scale, err := parseRetime(*retime)
+		if err != nil {
+			safeDialogMessage("Limesub v3 - Error", err.Error(), true)
+			return
+		}
+		opts.TimeScale, opts.TimeOffset = scale, 0
+		verbosef("retime: t' = t × %.6f", scale)
+	}
+	switch opts.LineBreak {
+	case "hard", "soft":
+	default:
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Nilai -linebreak %q tidak didukung.\n\nGunakan hard atau soft.", opts.LineBreak),
+			true)
+		return
+	}
+	switch opts.TimeUnit {
+	case "auto", "cs", "ms", "s":
+	default:
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Satuan waktu -time-unit %q tidak didukung.\n\nGunakan auto, cs, ms atau s.", opts.TimeUnit),
+			true)
+		return
+	}
+
+	if fs.NArg() < 1 {
+		safeDialogMessage("Limesub v3 - Informasi",
+			"Program ini hanya dapat dijalankan dengan cara:\n\n👉 Drag & drop file subtitle ke ikon program, atau\n👉 Jalankan melalui Command Line Interface (CLI):\n    limesub [convert|resample|inspect|join|split] <file>",
+			true)
+		return
+	}
+
+	if fs.NArg() > 1 && !opts.Check {
+		runBatch(fs.Args(), *jobs)
+		return
+	}
+	input := fs.Arg(0)
+	ext := inputExt(input)
+
+	if opts.Check {
+		if ext != ".ass" {
+			fmt.Fprintln(os.Stderr, "-check hanya untuk file .ass")
+			os.Exit(1)
+		}
+		data, err := readInput(input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Gagal membaca file:", err)
+			os.Exit(1)
+		}
+		warnings := checkFontSizeOverrides(string(data))
+		for _, w := range warnings {
+			fmt.Println(w)
+		}
+		fmt.Printf("%d peringatan ditemukan.\n", len(warnings))
+		return
+	}
+
+	if ext == ".zip" {
+		convertZip(input)
+		return
+	}
+
+	bench := &phaseTimer{}
+	verbosef("convert: %s (%s) → %s", input, ext, opts.To)
+	result, report, err := convertFile(input, bench)
+	if err == errUnsupportedFormat {
+		safeDialogMessage("Limesub v3 - Format Tidak Didukung",
+			"Format file ini tidak didukung.\n\nGunakan file dengan ekstensi .srt, .vtt, .ttml, .xml, .json, .sub, .csv, .tsv, .ass, .mkv, atau .zip (boleh dikompresi .gz, mis. .srt.gz).\nFile tanpa ekstensi atau .txt dikenali dari isinya.",
+			true)
+		return
+	}
+	if err != nil {
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Konversi gagal:\n\n%v", err),
+			true)
+		return
+	}
+
+	t := time.Now()
+	output := generateOutputName(input, "."+opts.To)
+	err = writeConverted(output, result)
+	bench.add("serialize", t)
+
+	if err != nil {
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Terjadi kesalahan saat menulis output:\n\n%v", err),
+			true)
+		return
+	}
+	if opts.Benchmark {
+		fmt.Print(bench.report())
+	}
+	summary := fmt.Sprintf("✅ Konversi selesai!\n\nFile berhasil disimpan sebagai:\n%s", output)
+	if warn := report.summary(); warn != "" {
+		// pengguna drag & drop tidak melihat terminal, jadi peringatan lewat dialog
+		safeDialogMessage("Limesub v3 - Peringatan", summary+"\n\n"+warn, false)
+		return
+	}
+	fmt.Println(summary)
+}
+
+// readInput seperti os.ReadFile, tapi file .gz (mis. .srt.gz dari API)
+// didekompresi dulu, jadi semua konverter bisa membacanya.
+func readInput(path string) ([]byte, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// openInput seperti os.Open dengan dekompresi .gz transparan.
+func openInput(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".gz") {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("gagal membuka gzip: %w", err)
+	}
+	return gzipInput{zr, f}, nil
+}
+
+// gzipInput menutup reader gzip sekaligus file di bawahnya.
+type gzipInput struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipInput) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
+
+// inputExt: ekstensi format input (huruf kecil), tanpa .gz: "ep01.srt.gz" → ".srt".
+func inputExt(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".gz" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+	return ext
+}
+
+// errUnsupportedFormat: ekstensi input tidak dikenali convertFile.
+var errUnsupportedFormat = fmt.Errorf("format file tidak didukung")
+
+// convertFile mengubah satu file subtitle (dipilih dari ekstensinya) ke ASS
+// limenime. Fase parse & transform dicatat ke bench.
+func convertFile(input string, bench *phaseTimer) (string, cueReport, error) {
+	// semua format berbasis SRT lewat sini, jadi pass SRT berlaku sama rata;
+	// -to srt berhenti di SRT yang dirapikan, tanpa membangun ASS
+	srtToASS := func(srt string) (string, cueReport) {
+		if opts.To == "srt" {
+			return cleanSRT(prepareSRT(srt))
+		}
+		return processSRTReport([]byte(prepareSRT(srt)))
+	}
+	errNotSRT := fmt.Errorf("-to srt hanya untuk input berbasis SRT (.srt, .vtt, .ttml, .xml, .json, .sub)")
+
+	var err error
+	var result string
+	var report cueReport
+	// format ditebak sekali; isi yang sudah terbaca untuk itu dipakai ulang
+	ext, sniffed := sniffInput(input)
+	t := time.Now()
+
+	switch ext {
+	case ".csv", ".tsv":
+		if opts.To == "srt" {
+			return "", report, errNotSRT
+		}
+		if opts.Timing != "" {
+			result, err = convertCueSheetWithTiming(input, opts.Timing)
+		} else {
+			result, report, err = convertCueSheetToASS(input)
+		}
+		if err != nil {
+			return "", report, fmt.Errorf("gagal memproses cue sheet: %w", err)
+		}
+		t = bench.add("parse", t)
+
+	case ".mkv":
+		if strings.EqualFold(filepath.Ext(input), ".gz") {
+			return "", report, fmt.Errorf("file MKV terkompresi gzip tidak didukung, ekstrak dulu")
+		}
+		var content, kind string
+		content, kind, err = extractMKVSubtitle(input, opts.Track)
+		if err != nil {
+			return "", report, fmt.Errorf("gagal memproses file MKV: %w", err)
+		}
+		t = bench.add("parse", t)
+		if kind == "srt" {
+			result, report = srtToASS(content)
+		} else if opts.To == "srt" {
+			return "", report, errNotSRT
+		} else if result, err = resampleLimenime(content); err != nil {
+			return "", report, fmt.Errorf("gagal me-resample track ASS: %w", err)
+		}
+
+	case ".ass":
+		if opts.To == "srt" {
+			return "", report, errNotSRT
+		}
+		// file dibaca sekali: parse = baca file, transform = resample
+		raw := sniffed
+		if raw == nil {
+			if raw, err = readInput(input); err != nil {
+				return "", report, fmt.Errorf("gagal membaca file ASS: %w", err)
+			}
+		}
+		t = bench.add("parse", t)
+		if result, err = resampleLimenime(string(raw)); err != nil {
+			return "", report, fmt.Errorf("gagal memproses file ASS: %w", err)
+		}
+
+	default:
+		srtData, err := srtFromInput(input, ext, sniffed)
+		if err != nil {
+			return "", report, err
+		}
+		t = bench.add("parse", t)
+		result, report = srtToASS(srtData)
+	}
+
+	if opts.Flatten {
+		result = flattenASS(result)
+	}
+	bench.add("transform", t)
+	return result, report, nil
+}
+
+// isDrawing: teks event memuat drawing (\p dengan level bukan 0).
+func isDrawing(text string) bool {
+	for _, m := range reResDrawLevel.FindAllStringSubmatch(text, -1) {
+		if drawLevel(m[1]) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// reFlattenTag: override posisi yang dibuang -flatten.
+var reFlattenTag = regexp.MustCompile(`\\(?:pos|move|org|i?clip)\s*\([^)]*\)|\\an?\d+`)
+
+// flattenASS (-flatten) meratakan semua Dialogue jadi dialog biasa di bawah
+// tengah: override posisi dibuang, style jadi Default, margin jadi 0. Tag
+// inline lain (\b, \i, \c, ...) tetap. Drawing (\p1 dst.) tidak punya arti
+// tanpa posisinya, jadi diubah ke Comment supaya tidak hilang.
+func flattenASS(content string) string {
+	lines := strings.Split(content, "\n")
+	var format []string
+	section := ""
+	for i, ln := range lines {
+		trim := strings.TrimSpace(ln)
+		lower := strings.ToLower(trim)
+		switch {
+		case strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]"):
+			section = lower
+		case section == "[events]" && strings.HasPrefix(lower, "format:"):
+			format = strings.Split(trim[len("format:"):], ",")
+			for k := range format {
+				format[k] = strings.ToLower(strings.TrimSpace(format[k]))
+			}
+		case section == "[events]" && strings.HasPrefix(lower, "dialogue:") && len(format) > 0:
+			parts := splitNPreserveTrailing(strings.TrimSpace(trim[len("dialogue:"):]), ',', len(format))
+			if len(parts) < len(format) {
+				continue
+			}
+			kind := "Dialogue"
+			for k, name := range format {
+				switch name {
+				case "style":
+					parts[k] = cfg.outStyle("Default")
+				case "marginl", "marginr", "marginv":
+					parts[k] = "0000"
+				case "text":
+					if isDrawing(parts[k]) {
+						kind = "Comment"
+						continue
+					}
+					parts[k] = replaceSubmatch(reSRTOverride, parts[k], func(sub []string) string {
+						inner := reFlattenTag.ReplaceAllString(sub[0], "")
+						if inner == "{}" {
+							return ""
+						}
+						return inner
+					})
+				}
+			}
+			lines[i] = kind + ": " + strings.Join(parts, ",")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readAsSRT membaca format berbasis SRT (.srt, .vtt, .ttml, .xml, .json, .sub) dan
+// mengembalikan SRT perantara sebelum processSRT. Format lain: errUnsupportedFormat.
+func readAsSRT(input string) (string, error) {
+	ext, data := sniffInput(input)
+	return srtFromInput(input, ext, data)
+}
+
+// srtFromInput seperti readAsSRT untuk format yang sudah diketahui (hasil
+// sniffInput); data yang sudah terbaca saat menebak format dipakai ulang.
+func srtFromInput(input, ext string, data []byte) (string, error) {
+	format := strings.TrimPrefix(ext, ".")
+	if !srtFormats[format] {
+		return "", errUnsupportedFormat
+	}
+	if data == nil {
+		var err error
+		if data, err = readInput(input); err != nil {
+			return "", fmt.Errorf("gagal membaca file: %w", err)
+		}
+	}
+	return srtFromData(data, format)
+}
+
+// srtFormats: format (nama ekstensi tanpa titik) yang dibaca lewat SRT perantara.
+var srtFormats = map[string]bool{
+	"srt": true, "vtt": true, "ttml": true, "xml": true, "json": true, "sub": true,
+}
+
+// srtFromData mengubah isi file berformat format (lihat srtFormats) ke SRT
+// perantara. XML dicoba berurutan: Custom XML, TTML, lalu XML generik.
+func srtFromData(data []byte, format string) (string, error) {
+	switch format {
+	case "ttml", "xml":
+		srtData, err := customXMLToSRT(data)
+		if err != nil {
+			verbosef("convert: bukan Custom XML (%v), coba TTML", err)
+			srtData, err = ttmlToSRT(data)
+			if _, ok := err.(ttmlLangError); ok {
+				return "", err
+			}
+			if err != nil {
+				verbosef("convert: bukan TTML (%v), coba XML generik", err)
+				// upaya terakhir: elemen apa pun yang punya atribut waktu + teks
+				if generic, gerr := genericXMLToSRT(bytes.NewReader(data)); gerr == nil {
+					srtData, err = generic, nil
+				}
+			}
+			if err != nil {
+				return "", fmt.Errorf("gagal memproses file XML/TTML: %w", err)
+			}
+		}
+		return srtData, nil
+
+	case "vtt":
+		srtData, err := vttToSRT(data)
+		if err != nil {
+			return "", fmt.Errorf("gagal memproses file VTT: %w", err)
+		}
+		return srtData, nil
+
+	case "srt":
+		return normalizeEOL(string(data)), nil
+
+	case "json":
+		srtData, err := jsonToSRT(data)
+		if err != nil {
+			return "", fmt.Errorf("gagal memproses file JSON: %w", err)
+		}
+		return srtData, nil
+
+	case "sub":
+		srtData, err := subToSRT(data)
+		if err != nil {
+			return "", fmt.Errorf("gagal memproses file SUB: %w", err)
+		}
+		return srtData, nil
+	}
+	return "", errUnsupportedFormat
+}
+
+// ConvertAny mengubah isi subtitle apa pun ke ASS limenime tanpa file, untuk
+// dipakai dari program lain. hint berupa nama format atau ekstensi ("vtt",
+// ".srt", "ttml", ...); kosong = format ditebak dari isi (sniffFormat).
+// Mengembalikan ASS dan format yang dipakai. Tidak pernah membaca file; cue
+// yang dibuang (timing rusak, durasi nol) tidak dilaporkan, sama seperti
+// processSRT.
+func ConvertAny(data []byte, hint string) (string, string, error) {
+	format := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hint), "."))
+	if format == "" {
+		format = sniffFormat(data)
+		if format == "" {
+			return "", "", fmt.Errorf("format subtitle tidak dikenali dari isinya")
+		}
+	}
+	switch format {
+	case "ass", "ssa":
+		out, err := resampleLimenime(string(data))
+		return out, "ass", err
+	case "csv", "tsv":
+		comma := ','
+		if format == "tsv" {
+			comma = '\t'
+		}
+		out, _, err := cueSheetToASS(bytes.NewReader(data), comma)
+		return out, format, err
+	}
+	srt, err := srtFromData(data, format)
+	if err != nil {
+		return "", format, err
+	}
+	srt = prepareSRT(srt)
+	if len(scanSRTCues(srt)) == 0 {
+		return "", format, fmt.Errorf("tidak ada cue ditemukan")
+	}
+	return processSRT([]byte(srt)), format, nil
+}
+
+// sniffFormat menebak format dari isi: "ass", "vtt", "ttml", "xml", "json",
+// "sub", "srt", atau "" jika tidak dikenali. Hanya 4 KB pertama yang dilihat,
+// kecuali JSON yang bentuknya (body/events/array) harus di-decode utuh.
+func sniffFormat(data []byte) string {
+	head := normalizeEOL(strings.TrimSpace(strings.TrimPrefix(string(data[:min(len(data), 4096)]), "\ufeff")))
+	lower := strings.ToLower(head)
+	switch {
+	case strings.HasPrefix(head, "WEBVTT"):
+		return "vtt"
+	case strings.HasPrefix(lower, "[script info]"):
+		return "ass"
+	case strings.HasPrefix(head, "<"):
+		if strings.Contains(lower, "<tt") {
+			return "ttml"
+		}
+		// <?xml ...> / <dia> (Custom XML) / XML generik: dicoba berurutan di srtFromData
+		return "xml"
+	case strings.HasPrefix(head, "{") || strings.HasPrefix(head, "["):
+		if kind, err := detectJSONFormat(data); err == nil && kind != "" {
+			return "json"
+		}
+	}
+	if detectSubDialect(head) != "" {
+		return "sub"
+	}
+	for _, ln := range strings.Split(head, "\n") {
+		if reSRTTimingLine.MatchString(strings.TrimSpace(ln)) {
+			return "srt"
+		}
+	}
+	return ""
+}
+
+// inputFormat: ekstensi format input seperti inputExt, tapi untuk file tanpa
+// ekstensi, .txt, atau ekstensi tak dikenal formatnya ditebak dari isi
+// (banyak caption unduhan tidak berekstensi). "" jika tetap tidak dikenali.
+func inputFormat(input string) string {
+	ext, _ := sniffInput(input)
+	return ext
+}
+
+// sniffInput seperti inputFormat, ditambah isi file bila sudah dibaca untuk
+// menebak format (nil jika ekstensinya cukup), supaya tidak dibaca ulang.
+func sniffInput(input string) (string, []byte) {
+	ext := inputExt(input)
+	switch name := strings.TrimPrefix(ext, "."); {
+	case srtFormats[name], name == "ass", name == "csv", name == "tsv", name == "mkv", name == "zip":
+		return ext, nil
+	}
+	data, err := readInput(input)
+	if err != nil {
+		return ext, nil
+	}
+	if f := sniffFormat(data); f != "" {
+		verbosef("convert: %s dikenali dari isinya sebagai %s", input, f)
+		return "." + f, data
+	}
+	return ext, data
+}
+
+// writeConverted mengekspor hasil ASS ke format -to lalu menulisnya ke output.
+func writeConverted(output, result string) error {
+	// export ke format lain lewat model cue dari hasil ASS
+	switch opts.To {
+	case "vtt":
+		result = writeVTT(parseASSCues(result))
+	case "csv":
+		result = writeCueSheet(parseASSCues(result), ',')
+	case "tsv":
+		result = writeCueSheet(parseASSCues(result), '\t')
+	case "txt":
+		result = writeTranscript(parseASSCues(result), opts.WithTimestamps)
+	}
+	verbosef("convert: menulis %s (crlf=%v, bom=%v)", output, opts.CRLF, opts.BOM)
+	return os.WriteFile(output, []byte(applyLineEndings(result)), 0644)
+}
+
+// ======================================
+// 🔹 Helper: subtitle teks di dalam MKV (Matroska)
+// ======================================
+// Pembaca EBML minimal tanpa dependensi: hanya elemen yang dibutuhkan untuk
+// track S_TEXT/ASS (SSA) dan S_TEXT/UTF8 yang dibaca, sisanya (video, audio,
+// attachment) dilompati dengan Seek sehingga file besar tidak dibaca utuh.
+
+// ID elemen Matroska yang dipakai
+const (
+	mkvSegment        = 0x18538067
+	mkvInfo           = 0x1549A966
+	mkvTimecodeScale  = 0x2AD7B1
+	mkvTracks         = 0x1654AE6B
+	mkvTrackEntry     = 0xAE
+	mkvTrackNumber    = 0xD7
+	mkvTrackType      = 0x83
+	mkvCodecID        = 0x86
+	mkvCodecPrivate   = 0x63A2
+	mkvLanguage       = 0x22B59C
+	mkvName           = 0x536E
+	mkvEncodings      = 0x6D80
+	mkvEncoding       = 0x6240
+	mkvCompression    = 0x5034
+	mkvCompAlgo       = 0x4254
+	mkvCompSettings   = 0x4255
+	mkvCluster        = 0x1F43B675
+	mkvClusterTime    = 0xE7
+	mkvBlockGroup     = 0xA0
+	mkvBlock          = 0xA1
+	mkvSimpleBlock    = 0xA3
+	mkvBlockDuration  = 0x9B
+	mkvTrackTypeSubs  = 0x11
+	mkvMaxElementSize = 16 << 20 // batas elemen yang dibaca ke memori (CodecPrivate, block subtitle)
+)
+
+// mkvMasters: elemen induk yang dimasuki. Semua elemen lain dilompati,
+// jadi ukuran "unknown" (live/streaming) pada induk tetap bisa dibaca.
+var mkvMasters = map[uint64]bool{
+	mkvSegment: true, mkvInfo: true, mkvTracks: true, mkvTrackEntry: true,
+	mkvEncodings: true, mkvEncoding: true, mkvCompression: true,
+	mkvCluster: true, mkvBlockGroup: true,
+}
+
+// mkvTrack: satu TrackEntry.
+type mkvTrack struct {
+	Number       uint64
+	Type         uint64
+	CodecID      string
+	CodecPrivate []byte
+	Language     string
+	Name         string
+	CompAlgo     int // -1 = tanpa kompresi, 0 = zlib, 3 = header stripping
+	CompSettings []byte
+}
+
+func (t mkvTrack) isText() bool {
+	switch t.CodecID {
+	case "S_TEXT/ASS", "S_TEXT/SSA", "S_TEXT/UTF8":
+		return t.Type == mkvTrackTypeSubs || t.Type == 0
+	}
+	return false
+}
+
+func (t mkvTrack) String() string {
+	s := fmt.Sprintf("%d (%s", t.Number, t.CodecID)
+	if t.Language != "" {
+		s += ", " + t.Language
+	}
+	if t.Name != "" {
+		s += ", " + t.Name
+	}
+	return s + ")"
+}
+
+// mkvBlockData: satu block subtitle, waktu dalam ms.
+type mkvBlockData struct {
+	Track      uint64
+	StartMs    int
+	DurationMs int // -1 = tidak ada BlockDuration (SimpleBlock)
+	Data       []byte
+}
+
+// readEBMLVint membaca variable-size integer EBML. keepMarker dipakai untuk ID
+// (bit penanda ikut jadi bagian nilai); ukuran yang semua bitnya 1 = unknown.
+func readEBMLVint(r io.Reader, keepMarker bool) (uint64, int, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:1]); err != nil {
+		return 0, 0, err
+	}
+	n := 1
+	for mask := byte(0x80); n <= 8 && b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if n > 8 {
+		return 0, 0, fmt.Errorf("vint EBML tidak valid (0x%02x)", b[0])
+	}
+	if n > 1 {
+		if _, err := io.ReadFull(r, b[1:n]); err != nil {
+			return 0, 0, err
+		}
+	}
+	v := uint64(b[0])
+	if !keepMarker {
+		v &= uint64(0xFF >> n)
+	}
+	allOnes := v == uint64(0xFF>>n)
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+		allOnes = allOnes && b[i] == 0xFF
+	}
+	if !keepMarker && allOnes {
+		return 0, n, errUnknownSize
+	}
+	return v, n, nil
+}
+
+var errUnknownSize = fmt.Errorf("ukuran elemen EBML unknown")
+
+func ebmlUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// readMKVSubtitles membaca semua track dan block subtitle teks dari r.
+func readMKVSubtitles(r io.ReadSeeker) ([]mkvTrack, []mkvBlockData, error) {
+	var tracks []mkvTrack
+	var blocks []mkvBlockData
+	timecodeScale := uint64(1000000) // ns per tick, default Matroska
+	var clusterTime uint64
+	lastInGroup := -1 // index block terakhir di BlockGroup, untuk BlockDuration
+
+	// track teks sudah diketahui saat cluster dibaca (Tracks selalu sebelum Cluster)
+	isTextTrack := func(n uint64) bool {
+		for _, t := range tracks {
+			if t.Number == n {
+				return t.isText()
+			}
+		}
+		return false
+	}
+
+	header, _, err := readEBMLVint(r, true)
+	if err != nil || header != 0x1A45DFA3 {
+		return nil, nil, fmt.Errorf("bukan file Matroska")
+	}
+	if size, _, err := readEBMLVint(r, false); err != nil {
+		return nil, nil, fmt.Errorf("header EBML rusak: %w", err)
+	} else if _, err := r.Seek(int64(size), io.SeekCurrent); err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		id, _, err := readEBMLVint(r, true)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		size, _, err := readEBMLVint(r, false)
+		unknown := err == errUnknownSize
+		if err != nil && !unknown {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, nil, err
+		}
+		if mkvMasters[id] {
+			switch id {
+			case mkvTrackEntry:
+				tracks = append(tracks, mkvTrack{CompAlgo: -1})
+			case mkvCompression:
+				if len(tracks) > 0 {
+					tracks[len(tracks)-1].CompAlgo = 0 // default ContentCompAlgo = zlib
+				}
+			case mkvBlockGroup:
+				lastInGroup = -1
+			}
+			continue // masuk ke anak-anaknya
+		}
+		if unknown {
+			return nil, nil, fmt.Errorf("elemen 0x%X berukuran unknown", id)
+		}
+
+		// block video/audio: cukup baca nomor track lalu lompati
+		if id == mkvSimpleBlock || id == mkvBlock {
+			track, n, err := readEBMLVint(r, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			rest := int64(size) - int64(n)
+			if !isTextTrack(track) || rest < 3 || rest > mkvMaxElementSize {
+				if _, err := r.Seek(rest, io.SeekCurrent); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			buf := make([]byte, rest)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return nil, nil, err
+			}
+			if buf[2]&0x06 != 0 {
+				verbosef("mkv: block track %d memakai lacing, dilewati", track)
+				continue
+			}
+			rel := int64(int16(uint16(buf[0])<<8 | uint16(buf[1])))
+			ticks := int64(clusterTime) + rel
+			blocks = append(blocks, mkvBlockData{
+				Track:      track,
+				StartMs:    int(ticks * int64(timecodeScale) / 1000000),
+				DurationMs: -1,
+				Data:       buf[3:],
+			})
+			if id == mkvBlock {
+				lastInGroup = len(blocks) - 1
+			}
+			continue
+		}
+
+		switch id {
+		case mkvTimecodeScale, mkvTrackNumber, mkvTrackType, mkvCodecID, mkvCodecPrivate,
+			mkvLanguage, mkvName, mkvCompAlgo, mkvCompSettings, mkvClusterTime, mkvBlockDuration:
+		default:
+			if _, err := r.Seek(int64(size), io.SeekCurrent); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if size > mkvMaxElementSize {
+			return nil, nil, fmt.Errorf("elemen 0x%X terlalu besar (%d byte)", id, size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, nil, err
+		}
+		var cur *mkvTrack
+		if len(tracks) > 0 {
+			cur = &tracks[len(tracks)-1]
+		}
+		switch {
+		case id == mkvTimecodeScale:
+			if v := ebmlUint(data); v > 0 {
+				timecodeScale = v
+			}
+		case id == mkvClusterTime:
+			clusterTime = ebmlUint(data)
+		case id == mkvBlockDuration:
+			if lastInGroup >= 0 {
+				blocks[lastInGroup].DurationMs = int(int64(ebmlUint(data)) * int64(timecodeScale) / 1000000)
+			}
+		case cur == nil:
+		case id == mkvTrackNumber:
+			cur.Number = ebmlUint(data)
+		case id == mkvTrackType:
+			cur.Type = ebmlUint(data)
+		case id == mkvCodecID:
+			cur.CodecID = strings.TrimRight(string(data), "\x00")
+		case id == mkvCodecPrivate:
+			cur.CodecPrivate = data
+		case id == mkvLanguage:
+			cur.Language = strings.TrimRight(string(data), "\x00")
+		case id == mkvName:
+			cur.Name = strings.TrimRight(string(data), "\x00")
+		case id == mkvCompAlgo:
+			cur.CompAlgo = int(ebmlUint(data))
+		case id == mkvCompSettings:
+			cur.CompSettings = data
+		}
+	}
+	return tracks, blocks, nil
+}
+
+// decode membuka kompresi block sesuai ContentEncoding track.
+func (t mkvTrack) decode(data []byte) ([]byte, error) {
+	switch t.CompAlgo {
+	case -1:
+		return data, nil
+	case 0:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case 3:
+		return append(append([]byte{}, t.CompSettings...), data...), nil
+	}
+	return nil, fmt.Errorf("kompresi track %d (algo %d) tidak didukung", t.Number, t.CompAlgo)
+}
+
+// extractMKVSubtitle mengambil track subtitle teks nomor trackNo (0 = track
+// teks pertama) dari file MKV. Hasilnya ASS utuh untuk S_TEXT/ASS & SSA, atau
+// SRT untuk S_TEXT/UTF8; kind berisi "ass" atau "srt".
+func extractMKVSubtitle(path string, trackNo int) (content, kind string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("gagal membuka file: %w", err)
+	}
+	defer f.Close()
+	tracks, blocks, err := readMKVSubtitles(f)
+	if err != nil {
+		return "", "", fmt.Errorf("gagal membaca MKV: %w", err)
+	}
+
+	var text []string
+	var track *mkvTrack
+	for i := range tracks {
+		if !tracks[i].isText() {
+			continue
+		}
+		text = append(text, tracks[i].String())
+		if track == nil && (trackNo == 0 || tracks[i].Number == uint64(trackNo)) {
+			track = &tracks[i]
+		}
+	}
+	if len(text) == 0 {
+		return "", "", fmt.Errorf("tidak ada track subtitle teks (ASS/SRT) di file ini")
+	}
+	if track == nil {
+		return "", "", fmt.Errorf("track %d bukan subtitle teks; yang tersedia: %s", trackNo, strings.Join(text, ", "))
+	}
+	verbosef("mkv: memakai track %s dari %d track teks", track, len(text))
+
+	type cue struct {
+		start, end int
+		order      int
+		data       string
+	}
+	var cues []cue
+	for _, b := range blocks {
+		if b.Track != track.Number {
+			continue
+		}
+		data, err := track.decode(b.Data)
+		if err != nil {
+			return "", "", err
+		}
+		cues = append(cues, cue{start: b.StartMs, end: b.StartMs + b.DurationMs, data: string(data)})
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].start < cues[j].start })
+	// SimpleBlock tanpa durasi: tampil sampai cue berikutnya (cue terakhir 5 detik)
+	for i := range cues {
+		if cues[i].end >= cues[i].start {
+			continue
+		}
+		cues[i].end = cues[i].start + 5000
+		if i+1 < len(cues) && cues[i+1].start > cues[i].start {
+			cues[i].end = cues[i+1].start
+		}
+	}
+
+	if track.CodecID == "S_TEXT/UTF8" {
+		var sb strings.Builder
+		for i, c := range cues {
+			fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", i+1,
+				formatTime(float64(c.start)/1000), formatTime(float64(c.end)/1000),
+				strings.TrimSpace(strings.ReplaceAll(c.data, "\r\n", "\n")))
+		}
+		return sb.String(), "srt", nil
+	}
+
+	// S_TEXT/ASS: CodecPrivate = header ([Script Info] s.d. Format [Events]),
+	// block = ReadOrder,Layer,Style,Name,MarginL,MarginR,MarginV,Effect,Text
+	for i := range cues {
+		parts := strings.SplitN(cues[i].data, ",", 2)
+		cues[i].order, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+		if len(parts) == 2 {
+			cues[i].data = parts[1]
+		}
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].order < cues[j].order })
+
+	header := strings.TrimRight(strings.ReplaceAll(string(track.CodecPrivate), "\r\n", "\n"), "\n\x00 ")
+	if !strings.Contains(strings.ToLower(header), "[events]") {
+		header += "\n\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text"
+	}
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, c := range cues {
+		layer, rest := c.data, ""
+		if k := strings.Index(c.data, ","); k >= 0 {
+			layer, rest = c.data[:k], c.data[k+1:]
+		}
+		fmt.Fprintf(&sb, "Dialogue: %s,%s,%s,%s\n", layer, msToASSTime(c.start), msToASSTime(c.end), rest)
+	}
+	return sb.String(), "ass", nil
+}
+
+// ======================================
+// 🔹 Helper: batch banyak file (-j)
+// ======================================
+
+var outputNameMu sync.Mutex
+
+// reserveOutputName memilih nama lewat generateOutputName lalu langsung membuat
+// file kosongnya, di bawah mutex, supaya dua worker tidak pernah mendapat nama
+// "(1)" yang sama. File kosong itu ditimpa writeConverted.
+func reserveOutputName(input, ext string) (string, error) {
+	outputNameMu.Lock()
+	defer outputNameMu.Unlock()
+	name := generateOutputName(input, ext)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// batchResult: hasil satu file input di mode batch.
+type batchResult struct {
+	Input   string
+	Outputs []string
+	Notes   []string // peringatan cue atau entri zip yang gagal
+	Err     error
+}
+
+// convertBatch mengonversi inputs memakai jobs worker; tiap worker mengerjakan
+// satu file dari baca sampai tulis. Urutan hasil sama dengan urutan inputs.
+func convertBatch(inputs []string, jobs int, bench *phaseTimer) []batchResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > len(inputs) {
+		jobs = len(inputs)
+	}
+	results := make([]batchResult, len(inputs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	var benchMu sync.Mutex
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				own := &phaseTimer{}
+				results[i] = convertOne(inputs[i], own)
+				benchMu.Lock()
+				bench.merge(own)
+				benchMu.Unlock()
+			}
+		}()
+	}
+	for i := range inputs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+// convertOne: satu file batch end-to-end. Panic diubah jadi error supaya satu
+// file rusak tidak menjatuhkan worker lain.
+func convertOne(input string, bench *phaseTimer) (res batchResult) {
+	res.Input = input
+	defer func() {
+		if r := recover(); r != nil {
+			res.Err = fmt.Errorf("kesalahan tak terduga: %v", r)
+		}
+	}()
+	verbosef("batch: %s", input)
+
+	if strings.EqualFold(filepath.Ext(input), ".zip") {
+		zr, err := zip.OpenReader(input)
+		if err != nil {
+			res.Err = fmt.Errorf("gagal membuka arsip ZIP: %w", err)
+			return
+		}
+		defer zr.Close()
+		outDir := strings.TrimSuffix(input, filepath.Ext(input))
+		res.Outputs, res.Notes, res.Err = convertZipArchive(&zr.Reader, outDir, bench)
+		return
+	}
+
+	result, report, err := convertFile(input, bench)
+	if err != nil {
+		res.Err = err
+		return
+	}
+	output, err := reserveOutputName(input, "."+opts.To)
+	if err != nil {
+		res.Err = fmt.Errorf("gagal menyiapkan file output: %w", err)
+		return
+	}
+	t := time.Now()
+	err = writeConverted(output, result)
+	bench.add("serialize", t)
+	if err != nil {
+		os.Remove(output)
+		res.Err = fmt.Errorf("gagal menulis output: %w", err)
+		return
+	}
+	res.Outputs = []string{output}
+	if warn := report.summary(); warn != "" {
+		res.Notes = append(res.Notes, warn)
+	}
+	return
+}
+
+// runBatch: beberapa file sekaligus (drag & drop banyak file atau CLI).
+func runBatch(inputs []string, jobs int) {
+	bench := &phaseTimer{}
+	results := convertBatch(inputs, jobs, bench)
+	if opts.Benchmark {
+		fmt.Print(bench.report())
+	}
+
+	ok := 0
+	var lines, problems []string
+	for _, r := range results {
+		if r.Err != nil {
+			problems = append(problems, fmt.Sprintf("✗ %s: %v", r.Input, r.Err))
+			continue
+		}
+		ok++
+		lines = append(lines, r.Outputs...)
+		for _, n := range r.Notes {
+			problems = append(problems, fmt.Sprintf("%s: %s", r.Input, n))
+		}
+	}
+	summary := fmt.Sprintf("✅ %d dari %d file berhasil dikonversi.", ok, len(inputs))
+	if len(problems) > 0 {
+		safeDialogMessage("Limesub v3 - Peringatan", summary+"\n\n"+strings.Join(problems, "\n"), ok == 0)
+		return
+	}
+	fmt.Println(summary + "\n\n" + strings.Join(lines, "\n"))
+}
 
-	if isError {
-		dialog.Message(msg).Title(title).Error()
+// ======================================
+// 🔹 Helper: paket subtitle .zip
+// ======================================
+
+// zipSubtitleExts: entri arsip yang dikonversi, sisanya (.txt, font, dll.) dilewati.
+var zipSubtitleExts = map[string]bool{
+	".srt": true, ".vtt": true, ".ttml": true, ".xml": true,
+	".json": true, ".sub": true, ".csv": true, ".tsv": true, ".ass": true,
+}
+
+// convertZip mengonversi semua subtitle di dalam arsip ke folder bersebelahan
+// yang bernama sama dengan arsipnya (mis. pack.zip → pack/).
+func convertZip(input string) {
+	zr, err := zip.OpenReader(input)
+	if err != nil {
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Gagal membuka arsip ZIP:\n\n%v", err),
+			true)
+		return
+	}
+	defer zr.Close()
+
+	outDir := strings.TrimSuffix(input, filepath.Ext(input))
+	bench := &phaseTimer{}
+	written, notes, err := convertZipArchive(&zr.Reader, outDir, bench)
+	if err != nil {
+		safeDialogMessage("Limesub v3 - Error",
+			fmt.Sprintf("Gagal memproses arsip ZIP:\n\n%v", err),
+			true)
+		return
+	}
+	if opts.Benchmark {
+		fmt.Print(bench.report())
+	}
+	summary := fmt.Sprintf("✅ %d file dari arsip dikonversi ke folder:\n%s", len(written), outDir)
+	if len(written) == 0 || len(notes) > 0 {
+		safeDialogMessage("Limesub v3 - Peringatan", summary+"\n\n"+strings.Join(notes, "\n"), len(written) == 0)
+		return
+	}
+	fmt.Println(summary)
+}
+
+// convertZipArchive mengonversi tiap entri subtitle di zr ke outDir. Entri yang
+// gagal tidak menghentikan entri lain; kegagalan dan peringatannya dikembalikan
+// di notes. Nama entri hanya dipakai base-nya, jadi path di dalam arsip
+// (termasuk "../") tidak pernah keluar dari outDir.
+func convertZipArchive(zr *zip.Reader, outDir string, bench *phaseTimer) (written, notes []string, err error) {
+	tmp, err := os.MkdirTemp("", "limesub-zip-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	for i, f := range zr.File {
+		base := filepath.Base(filepath.FromSlash(f.Name))
+		if f.FileInfo().IsDir() || !zipSubtitleExts[strings.ToLower(filepath.Ext(base))] {
+			verbosef("zip: lewati %s", f.Name)
+			continue
+		}
+		// tiap entri di subfolder sendiri supaya nama yang sama tidak bertabrakan
+		src := filepath.Join(tmp, strconv.Itoa(i), base)
+		if err := extractZipEntry(f, src); err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", f.Name, err))
+			continue
+		}
+		verbosef("zip: konversi %s", f.Name)
+		result, report, err := convertFile(src, bench)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", f.Name, err))
+			continue
+		}
+		output, err := reserveOutputName(filepath.Join(outDir, base), "."+opts.To)
+		if err != nil {
+			return written, notes, err
+		}
+		t := time.Now()
+		err = writeConverted(output, result)
+		bench.add("serialize", t)
+		if err != nil {
+			os.Remove(output)
+			return written, notes, err
+		}
+		written = append(written, output)
+		if warn := report.summary(); warn != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s", f.Name, warn))
+		}
+	}
+	if len(written) == 0 && len(notes) == 0 {
+		notes = append(notes, "tidak ada file subtitle di dalam arsip")
+	}
+	return written, notes, nil
+}
+
+// extractZipEntry menyalin isi entri f ke file dst.
+func extractZipEntry(f *zip.File, dst string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// runResample: ASS → resolusi target (default 1920x1080) tanpa konversi lain.
+func runResample(args []string) {
+	fs := flag.NewFlagSet("resample", flag.ExitOnError)
+	res := fs.String("res", cfg.resolution(), "resolusi target, format LEBARxTINGGI")
+	font := fs.String("font", cfg.fontName(), "ganti font semua style dan \\fn ke font ini (kosong = biarkan)")
+	resStyle := fs.Bool("res-style", true, "tambahkan style \"res\" limenime di akhir [V4+ Styles]")
+	fs.IntVar(&opts.Track, "track", 0, "MKV: nomor track ASS yang diambil (default: track teks pertama)")
+	fs.BoolVar(&opts.Verbose, "v", false, "cetak langkah yang dilakukan ke stderr")
+	resolveLineEndings := lineEndingFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Pemakaian: limesub resample [flag] <file.ass|file.mkv>")
+		fs.PrintDefaults()
+	}
+	if err := cfg.applyFlags(fs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fs.Parse(args)
+	resolveLineEndings("ass")
+	if cfgPath != "" {
+		verbosef("config: memakai %s", cfgPath)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	input := fs.Arg(0)
+	w, h, err := parseResolution(*res)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var content string
+	if strings.EqualFold(filepath.Ext(input), ".mkv") {
+		var kind string
+		content, kind, err = extractMKVSubtitle(input, opts.Track)
+		if err == nil && kind != "ass" {
+			err = fmt.Errorf("track %d bukan ASS; pakai subcommand convert untuk track SRT", opts.Track)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Gagal mengambil subtitle MKV:", err)
+			os.Exit(1)
+		}
 	} else {
-		dialog.Message(msg).Title(title).Info()
+		data, err := readInput(input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Gagal membaca file:", err)
+			os.Exit(1)
+		}
+		content = string(data)
+	}
+
+	var ropts []Option
+	if *font != "" {
+		ropts = append(ropts, WithFontName(*font))
+	}
+	if *resStyle {
+		ropts = append(ropts, WithExtraStyle(cfg.style("res", resStyleLine)))
+	}
+	result, err := ResampleASS(content, w, h, ropts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Gagal me-resample file ASS:", err)
+		os.Exit(1)
+	}
+	output := generateOutputName(input, ".ass")
+	if err := os.WriteFile(output, []byte(applyLineEndings(result)), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "Gagal menulis output:", err)
+		os.Exit(1)
 	}
+	fmt.Println("Berhasil disimpan:", output)
 }
 
+// runJoin menyambung beberapa file subtitle (mis. CD1 + CD2) jadi satu ASS
+// limenime, lewat SRT perantara yang sama dengan convert.
+func runJoin(args []string) {
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	var shifts []int
+	fs.Func("shift", "offset file ke-2, ke-3, dst. (ulangi flag untuk tiap file, urut), mis. 00:45:30,000; default: akhir file sebelumnya",
+		func(s string) error {
+			ms, err := parseShift(s)
+			if err != nil {
+				return err
+			}
+			shifts = append(shifts, ms)
+			return nil
+		})
+	output := fs.String("o", "", "file output (default: <file pertama>.ass)")
+	fs.BoolVar(&opts.FixTimes, "fix-times", false, "tukar balik cue yang start-nya lebih besar dari end (default: cue dibuang)")
+	fs.BoolVar(&opts.Verbose, "v", false, "cetak langkah yang dilakukan ke stderr")
+	resolveLineEndings := lineEndingFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Pemakaian: limesub join [flag] <file1> <file2> [file...] [-o output.ass]")
+		fs.PrintDefaults()
+	}
+	if err := cfg.applyFlags(fs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// flag boleh diselipkan di antara/di belakang nama file
+	var inputs []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			break
+		}
+		inputs = append(inputs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	resolveLineEndings("ass")
+	if cfgPath != "" {
+		verbosef("config: memakai %s", cfgPath)
+	}
+	if len(inputs) < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
 
-// ======================================
-// Entry point utama
-// ======================================
-func main() {
-	defer func() {
-		if r := recover(); r != nil {
-			safeDialogMessage("Limesub v3 - Error",
-				fmt.Sprintf("Terjadi kesalahan tak terduga:\n\n%v", r),
-				true)
+	parts := make([]string, len(inputs))
+	for i, input := range inputs {
+		srt, err := readAsSRT(input)
+		if err == errUnsupportedFormat {
+			err = fmt.Errorf("format tidak didukung untuk join (pakai .srt, .vtt, .ttml, .xml, .json atau .sub)")
 		}
-	}()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Gagal membaca %s: %v\n", input, err)
+			os.Exit(1)
+		}
+		parts[i] = prepareSRT(srt)
+		verbosef("join: %s", input)
+	}
+	result, report := processSRTReport([]byte(joinSRT(parts, shifts)))
 
-	if len(os.Args) < 2 {
-		safeDialogMessage("Limesub v3 - Informasi",
-			"Program ini hanya dapat dijalankan dengan cara:\n\n👉 Drag & drop file subtitle ke ikon program, atau\n👉 Jalankan melalui Command Line Interface (CLI).",
-			true)
+	out := *output
+	if out == "" {
+		out = generateOutputName(inputs[0], ".ass")
+	}
+	if err := os.WriteFile(out, []byte(applyLineEndings(result)), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "Gagal menulis output:", err)
+		os.Exit(1)
+	}
+	if warn := report.summary(); warn != "" {
+		fmt.Fprintln(os.Stderr, warn)
+	}
+	fmt.Println("Berhasil disimpan:", out)
+}
+
+// runSplit memotong satu file subtitle di -at menjadi <nama>_part1 dan
+// <nama>_part2 (waktu part2 dimulai dari nol), masing-masing ASS limenime.
+func runSplit(args []string) {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	at := fs.String("at", "", "titik potong, mis. 00:45:30,000 (wajib)")
+	fs.BoolVar(&opts.FixTimes, "fix-times", false, "tukar balik cue yang start-nya lebih besar dari end (default: cue dibuang)")
+	fs.BoolVar(&opts.Verbose, "v", false, "cetak langkah yang dilakukan ke stderr")
+	resolveLineEndings := lineEndingFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Pemakaian: limesub split -at 00:45:30,000 [flag] <file>")
+		fs.PrintDefaults()
+	}
+	if err := cfg.applyFlags(fs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fs.Parse(args)
+	resolveLineEndings("ass")
+	if cfgPath != "" {
+		verbosef("config: memakai %s", cfgPath)
+	}
+	if fs.NArg() < 1 || *at == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cut, err := parseShift(*at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "nilai -at %q tidak valid, contoh: 00:45:30,000\n", *at)
+		os.Exit(2)
+	}
+
+	input := fs.Arg(0)
+	srt, err := readAsSRT(input)
+	if err == errUnsupportedFormat {
+		err = fmt.Errorf("format tidak didukung untuk split (pakai .srt, .vtt, .ttml, .xml, .json atau .sub)")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal membaca %s: %v\n", input, err)
+		os.Exit(1)
+	}
+	before, after := splitSRT(prepareSRT(srt), cut)
+
+	ext := filepath.Ext(input)
+	base := strings.TrimSuffix(input, ext)
+	for i, part := range []string{before, after} {
+		// titik potong sebelum cue pertama / sesudah cue terakhir
+		if part == "" {
+			fmt.Fprintf(os.Stderr, "peringatan: part %d tidak berisi cue, tidak ditulis\n", i+1)
+			continue
+		}
+		result, report := processSRTReport([]byte(part))
+		out := generateOutputName(fmt.Sprintf("%s_part%d%s", base, i+1, ext), ".ass")
+		verbosef("split: part %d → %s", i+1, out)
+		if err := os.WriteFile(out, []byte(applyLineEndings(result)), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "Gagal menulis output:", err)
+			os.Exit(1)
+		}
+		if warn := report.summary(); warn != "" {
+			fmt.Fprintln(os.Stderr, warn)
+		}
+		fmt.Println("Berhasil disimpan:", out)
+	}
+}
+
+// parseResolution: "1920x1080" → 1920, 1080
+func parseResolution(s string) (int, int, error) {
+	var w, h int
+	if n, err := fmt.Sscanf(strings.ToLower(strings.TrimSpace(s)), "%dx%d", &w, &h); n != 2 || err != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("resolusi -res %q tidak valid, contoh: 1920x1080", s)
+	}
+	return w, h, nil
+}
+
+// runInspect mencetak metadata file subtitle tanpa menulis file apa pun.
+func runInspect(args []string) {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "cetak hasil sebagai JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Pemakaian: limesub inspect [-json] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	info, err := inspectFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Gagal memeriksa file:", err)
+		os.Exit(1)
+	}
+	if *asJSON {
+		out, _ := json.MarshalIndent(info, "", "  ")
+		fmt.Println(string(out))
 		return
 	}
+	fmt.Print(info.String())
+}
+
+// inspectInfo: ringkasan satu file subtitle. PlayRes dan style hanya terisi
+// untuk ASS.
+type inspectInfo struct {
+	Format   string   `json:"format"`
+	Cues     int      `json:"cues"`
+	Start    string   `json:"start,omitempty"` // cue paling awal (H:MM:SS.mmm)
+	End      string   `json:"end,omitempty"`   // akhir cue paling akhir
+	PlayResX float64  `json:"playResX,omitempty"`
+	PlayResY float64  `json:"playResY,omitempty"`
+	Styles   []string `json:"styles,omitempty"`
+}
+
+func (in inspectInfo) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "format:  %s\n", in.Format)
+	fmt.Fprintf(&sb, "cue:     %d\n", in.Cues)
+	if in.Start != "" {
+		fmt.Fprintf(&sb, "rentang: %s → %s\n", in.Start, in.End)
+	}
+	if in.PlayResX > 0 {
+		fmt.Fprintf(&sb, "PlayRes: %sx%s\n", formatScaled(in.PlayResX), formatScaled(in.PlayResY))
+		fmt.Fprintf(&sb, "style:   %s\n", strings.Join(in.Styles, ", "))
+	}
+	return sb.String()
+}
 
-	input := os.Args[1]
-	ext := strings.ToLower(filepath.Ext(input))
+// setSpan mengisi Start/End dari cue paling awal dan paling akhir.
+func (in *inspectInfo) setSpan(cues []Cue) {
+	if len(cues) == 0 {
+		return
+	}
+	start, end := cues[0].Start, cues[0].End
+	for _, c := range cues[1:] {
+		if c.Start < start {
+			start = c.Start
+		}
+		if c.End > end {
+			end = c.End
+		}
+	}
+	in.Start, in.End = msToVTTTime(start), msToVTTTime(end)
+}
 
+// inspectFile mendeteksi format dengan urutan parser yang sama seperti convert.
+// Format selain ASS dihitung cue-nya dari hasil SRT, rentang waktunya dari
+// hasil processSRT.
+func inspectFile(input string) (inspectInfo, error) {
+	ext := inputFormat(input)
+	var info inspectInfo
 	var srtData string
 	var err error
-	var output string
 
 	switch ext {
-	case ".ttml", ".xml":
-		srtData, err = convertCustomXMLtoSRT(input)
+	case ".ass":
+		data, err := readInput(input)
 		if err != nil {
-			srtData, err = convertTTMLtoSRT(input)
-			if err != nil {
-				safeDialogMessage("Limesub v3 - Error",
-					fmt.Sprintf("Gagal memproses file XML/TTML:\n\n%v", err),
-					true)
-				return
+			return info, fmt.Errorf("gagal membaca file: %w", err)
+		}
+		doc := parseASSCues(string(data))
+		info.Format = "ASS"
+		info.Cues = len(doc.Cues)
+		info.setSpan(doc.Cues)
+		info.PlayResX, info.PlayResY = doc.PlayResX, doc.PlayResY
+		for name := range doc.StyleAlign {
+			info.Styles = append(info.Styles, name)
+		}
+		sort.Strings(info.Styles)
+		return info, nil
+
+	case ".csv", ".tsv":
+		result, _, err := convertCueSheetToASS(input)
+		if err != nil {
+			return info, err
+		}
+		doc := parseASSCues(result)
+		info.Format = strings.ToUpper(ext[1:])
+		info.Cues = len(doc.Cues)
+		info.setSpan(doc.Cues)
+		return info, nil
+
+	case ".ttml", ".xml":
+		info.Format = "Custom XML"
+		if srtData, err = convertCustomXMLtoSRT(input); err != nil {
+			info.Format = "TTML"
+			if srtData, err = convertTTMLtoSRT(input); err != nil {
+				info.Format = "XML generik"
+				srtData, err = convertGenericXMLtoSRT(input)
 			}
 		}
-		output = generateOutputName(input)
-		result := processSRT(srtData)
-		err = os.WriteFile(output, []byte(result), 0644)
 
 	case ".vtt":
+		info.Format = "WebVTT"
 		srtData, err = convertVTTtoSRT(input)
-		if err != nil {
-			safeDialogMessage("Limesub v3 - Error",
-				fmt.Sprintf("Gagal memproses file VTT:\n\n%v", err),
-				true)
-			return
-		}
-		output = generateOutputName(input)
-		result := processSRT(srtData)
-		err = os.WriteFile(output, []byte(result), 0644)
 
 	case ".srt":
-		data, _ := os.ReadFile(input)
+		info.Format = "SRT"
+		var data []byte
+		data, err = readInput(input)
 		srtData = string(data)
-		output = generateOutputName(input)
-		result := processSRT(srtData)
-		err = os.WriteFile(output, []byte(result), 0644)
 
 	case ".json":
-		srtData, err = convertJSONtoSRT(input)
-		if err != nil {
-			safeDialogMessage("Limesub v3 - Error",
-				fmt.Sprintf("Gagal memproses file JSON:\n\n%v", err),
-				true)
-			return
+		var data []byte
+		if data, err = readInput(input); err != nil {
+			return info, fmt.Errorf("gagal membaca file: %w", err)
+		}
+		var kind string
+		if kind, err = detectJSONFormat(data); err != nil {
+			return info, err
 		}
-		output = generateOutputName(input)
-		result := processSRT(srtData)
-		err = os.WriteFile(output, []byte(result), 0644)
+		info.Format = "JSON (" + kind + ")"
+		srtData, err = convertJSONtoSRT(input)
 
-	case ".ass":
-		srtData, err = processASS(input)
-		if err != nil {
-			safeDialogMessage("Limesub v3 - Error",
-				fmt.Sprintf("Gagal memproses file ASS:\n\n%v", err),
-				true)
-			return
+	case ".sub":
+		var data []byte
+		if data, err = readInput(input); err != nil {
+			return info, fmt.Errorf("gagal membaca file: %w", err)
 		}
-		output = generateOutputName(input)
-		err = os.WriteFile(output, []byte(srtData), 0644)
+		info.Format = "MicroDVD"
+		if detectSubDialect(normalizeEOL(string(data))) == "subviewer" {
+			info.Format = "SubViewer 2.0"
+		}
+		srtData, err = convertSubToSRT(input)
 
 	default:
-		safeDialogMessage("Limesub v3 - Format Tidak Didukung",
-			"Format file ini tidak didukung.\n\nGunakan file dengan ekstensi .srt, .vtt, .ttml, .xml, .json, atau .ass.",
-			true)
-		return
+		return info, fmt.Errorf("format file %s tidak didukung", ext)
 	}
-
 	if err != nil {
-		safeDialogMessage("Limesub v3 - Error",
-			fmt.Sprintf("Terjadi kesalahan saat menulis output:\n\n%v", err),
-			true)
-		return
+		return info, err
+	}
+
+	for _, ln := range strings.Split(srtData, "\n") {
+		if strings.Contains(ln, " --> ") {
+			info.Cues++
+		}
+	}
+	info.setSpan(parseASSCues(processSRT([]byte(srtData))).Cues)
+	return info, nil
+}
+
+// ======================================
+// 🔹 Helper: event YouTube → SRT
+// ======================================
+
+// ytEventsToSRT membangun SRT dari event YouTube. Event "append" dari rolling
+// caption yang segmennya hanya "\n"/spasi dibuang. Dengan -yt-coalesce, caption
+// yang jendela waktunya tumpang tindih digabung jadi satu cue (maks. 2 baris).
+// Mengembalikan "" jika tidak ada caption valid.
+func ytEventsToSRT(events []ytEvent) string {
+	type caption struct {
+		Start float64
+		End   float64
+		Text  string
+	}
+	var caps []caption
+	for _, ev := range events {
+		if len(ev.Segs) == 0 {
+			continue
+		}
+		start := ev.TStartMs / 1000.0
+		end := (ev.TStartMs + ev.DDurationMs) / 1000.0
+		txt := ytEventText(ev)
+		// skip empty (termasuk yang isinya hanya tag \k)
+		if strings.TrimSpace(reASSOverride.ReplaceAllString(txt, "")) == "" {
+			continue
+		}
+		caps = append(caps, caption{Start: start, End: end, Text: txt})
+	}
+	// sort by start
+	sort.SliceStable(caps, func(i, j int) bool { return caps[i].Start < caps[j].Start })
+
+	if opts.YTCoalesce {
+		var out []caption
+		for _, c := range caps {
+			if n := len(out); n > 0 && c.Start < out[n-1].End && strings.Count(out[n-1].Text, "\n") == 0 {
+				last := &out[n-1]
+				last.Text += "\n" + c.Text
+				if c.End > last.End {
+					last.End = c.End
+				}
+				continue
+			}
+			if n := len(out); n > 0 && c.Start < out[n-1].End {
+				// cue sebelumnya sudah penuh: potong supaya tidak bertumpuk
+				out[n-1].End = c.Start
+			}
+			out = append(out, c)
+		}
+		caps = out
+	}
+
+	var sb strings.Builder
+	for i, c := range caps {
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, formatTime(c.Start), formatTime(c.End), strings.TrimSpace(c.Text)))
+	}
+	return sb.String()
+}
+
+// ======================================
+// 🔹 Helper: akhir baris & BOM output (-crlf / -bom)
+// ======================================
+
+// lineEndingFlags mendaftarkan -crlf dan -bom di fs. Fungsi yang dikembalikan
+// dipanggil setelah fs.Parse: flag yang tidak diisi mengikuti format output
+// (ASS: BOM + CRLF seperti Aegisub, SRT: CRLF tanpa BOM, format lain: LF
+// tanpa BOM).
+func lineEndingFlags(fs *flag.FlagSet) func(outFormat string) {
+	fs.BoolVar(&opts.CRLF, "crlf", false, "akhir baris CRLF (default: ya untuk output .ass dan .srt, tidak untuk format lain)")
+	fs.BoolVar(&opts.BOM, "bom", false, "tulis BOM UTF-8 di awal file (default: ya untuk output .ass, tidak untuk format lain)")
+	return func(outFormat string) {
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if !set["crlf"] {
+			opts.CRLF = outFormat == "ass" || outFormat == "srt"
+		}
+		if !set["bom"] {
+			opts.BOM = outFormat == "ass"
+		}
+	}
+}
+
+// normalizeEOL mengubah CRLF dan CR tunggal (Mac lama) jadi LF. Dipanggil di
+// awal setiap parser supaya tidak ada \r yang tertinggal di teks atau bikin
+// deteksi header/timing meleset.
+func normalizeEOL(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+// applyLineEndings menyeragamkan akhir baris ke LF, lalu menerapkan CRLF/BOM
+// sesuai opts. Dipakai semua jalur yang menulis file output.
+func applyLineEndings(s string) string {
+	s = normalizeEOL(strings.TrimPrefix(s, "\ufeff"))
+	if opts.CRLF {
+		s = strings.ReplaceAll(s, "\n", "\r\n")
+	}
+	if opts.BOM {
+		s = "\ufeff" + s
+	}
+	return s
+}
+
+// verbosef mencetak log langkah ke stderr hanya dengan -v, jadi drag & drop
+// tetap senyap.
+func verbosef(format string, args ...interface{}) {
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
+// ======================================
+// 🔹 Helper: pengukur waktu per fase (-benchmark)
+// ======================================
+type phaseTimer struct {
+	names []string
+	spent map[string]time.Duration
+}
+
+// add mencatat waktu sejak start ke fase name dan mengembalikan waktu sekarang
+// sebagai titik awal fase berikutnya.
+func (p *phaseTimer) add(name string, start time.Time) time.Time {
+	now := time.Now()
+	if p.spent == nil {
+		p.spent = map[string]time.Duration{}
+	}
+	if _, ok := p.spent[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.spent[name] += now.Sub(start)
+	return now
+}
+
+// merge menambahkan catatan o ke p (dipakai mode batch, satu timer per worker).
+func (p *phaseTimer) merge(o *phaseTimer) {
+	for _, name := range o.names {
+		if p.spent == nil {
+			p.spent = map[string]time.Duration{}
+		}
+		if _, ok := p.spent[name]; !ok {
+			p.names = append(p.names, name)
+		}
+		p.spent[name] += o.spent[name]
+	}
+}
+
+func (p *phaseTimer) report() string {
+	var sb strings.Builder
+	var total time.Duration
+	for _, name := range p.names {
+		fmt.Fprintf(&sb, "%-10s %v\n", name, p.spent[name])
+		total += p.spent[name]
 	}
-	fmt.Sprintf("✅ Konversi selesai!\n\nFile berhasil disimpan sebagai:\n%s", output)
+	fmt.Fprintf(&sb, "%-10s %v\n", "total", total)
+	return sb.String()
 }
 
 // ======================================
 // 🔹 Penamaan file otomatis
 // ======================================
-func generateOutputName(input string) string {
+func generateOutputName(input, ext string) string {
+	if strings.EqualFold(filepath.Ext(input), ".gz") {
+		input = strings.TrimSuffix(input, filepath.Ext(input))
+	}
 	base := strings.TrimSuffix(input, filepath.Ext(input))
-	out := base + "_Limenime.ass"
+	out := base + "_Limenime" + ext
 	count := 1
 	for {
 		if _, err := os.Stat(out); os.IsNotExist(err) {
 			break
 		}
-		out = fmt.Sprintf("%s_Limenime(%d).ass", base, count)
+		out = fmt.Sprintf("%s_Limenime(%d)%s", base, count, ext)
 		count++
 	}
 	return out
@@ -0,0 +1,2475 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// go test -update menulis ulang file *.golden di testdata/ dari output sekarang.
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata/")
+
+// ======================================
+// 🔹 Helper test
+// ======================================
+
+// withOpts mengembalikan opts, cfg, dan template header ke nilai semula setelah
+// test selesai, supaya flag yang di-set satu test tidak bocor ke test lain.
+func withOpts(t *testing.T) {
+	t.Helper()
+	savedOpts, savedCfg, savedHeader := opts, cfg, assHeaderTemplate
+	t.Cleanup(func() {
+		opts, cfg, assHeaderTemplate = savedOpts, savedCfg, savedHeader
+	})
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("gagal membaca fixture: %v", err)
+	}
+	return data
+}
+
+// checkGolden membandingkan got dengan testdata/<name>. Akhir baris disamakan
+// dulu supaya checkout dengan autocrlf tidak bikin test gagal.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("gagal menulis golden: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("gagal membaca golden (jalankan go test -update): %v", err)
+	}
+	if normalizeEOL(got) != normalizeEOL(string(want)) {
+		t.Errorf("output beda dari %s\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// dialogues: hanya baris Dialogue:/Comment: dari output ASS.
+func dialogues(ass string) []string {
+	var out []string
+	for _, l := range strings.Split(normalizeEOL(ass), "\n") {
+		if strings.HasPrefix(l, "Dialogue:") || strings.HasPrefix(l, "Comment:") {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// dialogueText: kolom Text dari satu baris Dialogue:.
+func dialogueText(line string) string {
+	return splitNPreserveTrailing(line, ',', 10)[9]
+}
+
+// ======================================
+// 🔹 Golden test konverter
+// ======================================
+
+func TestConvertersGolden(t *testing.T) {
+	withOpts(t)
+	srtToASS := func(data []byte) (string, error) { return processSRT(string(data)), nil }
+	assToASS := func(data []byte) (string, error) { return resampleLimenime(string(data)) }
+	xmlToSRT := func(data []byte) (string, error) { return srtFromData(data, "xml") }
+
+	tests := []struct {
+		input, golden string
+		convert       func([]byte) (string, error)
+	}{
+		{"basic.vtt", "basic.vtt.srt.golden", vttToSRT},
+		{"basic.ttml", "basic.ttml.srt.golden", ttmlToSRT},
+		{"bilibili.json", "bilibili.json.srt.golden", jsonToSRT},
+		{"youtube.json", "youtube.json.srt.golden", jsonToSRT},
+		{"custom.xml", "custom.xml.srt.golden", customXMLToSRT},
+		{"subtitle.xml", "subtitle.xml.srt.golden", xmlToSRT},
+		{"microdvd.sub", "microdvd.sub.srt.golden", subToSRT},
+		{"subviewer.sub", "subviewer.sub.srt.golden", subToSRT},
+		{"basic.srt", "basic.srt.ass.golden", srtToASS},
+		{"basic720.ass", "basic720.ass.golden", assToASS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := tt.convert(readFixture(t, tt.input))
+			if err != nil {
+				t.Fatalf("konversi gagal: %v", err)
+			}
+			checkGolden(t, tt.golden, got)
+		})
+	}
+}
+
+// Varian yang membaca file harus memberi hasil yang sama dengan varian in-memory.
+func TestConvertersFromFile(t *testing.T) {
+	withOpts(t)
+	tests := []struct {
+		input    string
+		fromFile func(string) (string, error)
+		inMemory func([]byte) (string, error)
+	}{
+		{"basic.vtt", convertVTTtoSRT, vttToSRT},
+		{"basic.ttml", convertTTMLtoSRT, ttmlToSRT},
+		{"bilibili.json", convertJSONtoSRT, jsonToSRT},
+		{"custom.xml", convertCustomXMLtoSRT, customXMLToSRT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			a, err := tt.fromFile(filepath.Join("testdata", tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := tt.inMemory(readFixture(t, tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a != b {
+				t.Errorf("file vs in-memory beda:\n%s\n---\n%s", a, b)
+			}
+		})
+	}
+}
+
+// ======================================
+// 🔹 Deteksi tanda (-detect-sign)
+// ======================================
+
+// Detektor multi-sinyal dibandingkan dengan aturan lama (kapital/kurung) pada
+// cue contoh; kasus non-Latin adalah alasan detektor ini ada.
+func TestSignDetectorVsCaps(t *testing.T) {
+	withOpts(t)
+	tests := []struct {
+		name     string
+		text     string
+		durMs    int
+		caps     string // defineStyle tanpa -detect-sign
+		detector string // defineStyle dengan -detect-sign
+	}{
+		{"dialog biasa", "Where are you going?", 2500, "Default", "Default"},
+		{"teriak kapital", "WHAT ARE YOU DOING?!", 2000, "tanda", "Default"},
+		{"papan kapital singkat", "STASIUN SHIBUYA", 900, "tanda", "tanda"},
+		{"papan dalam kurung", "[Toko Roti]", 3000, "tanda", "tanda"},
+		{"tanda Jepang berposisi", `{\an8}駅前商店街`, 800, "Default", "tanda"},
+		{"tanda \\pos huruf kecil", `{\pos(320,180)}exit only`, 1200, "Default", "tanda"},
+		{"dialog Jepang", "どこへ行くの？", 2000, "Default", "Default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts.DetectSign = false
+			if got := defineStyle(tt.text, tt.durMs); got != tt.caps {
+				t.Errorf("caps-only: got %q, want %q", got, tt.caps)
+			}
+			opts.DetectSign = true
+			opts.SignWeights = defaultSignWeights
+			if got := defineStyle(tt.text, tt.durMs); got != tt.detector {
+				t.Errorf("detector: got %q, want %q (skor %.1f)", got, tt.detector, signScore(tt.text, tt.durMs, defaultSignWeights))
+			}
+		})
+	}
+}
+
+func TestParseSignWeights(t *testing.T) {
+	w, err := parseSignWeights("caps=0, pos=3,threshold=2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Caps != 0 || w.Positioned != 3 || w.Threshold != 2.5 || w.Bracket != defaultSignWeights.Bracket {
+		t.Errorf("bobot salah: %+v", w)
+	}
+	if _, err := parseSignWeights("warna=1"); err == nil {
+		t.Error("kunci tidak dikenal harus error")
+	}
+}
+
+// ======================================
+// 🔹 Diagnostik -check
+// ======================================
+
+const fsCheckASS = `[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,40,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,2,20,20,30,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\fs48}wajar
+Dialogue: 0,0:00:03.00,0:00:04.00,Default,,0,0,0,,{\fs200}typo
+`
+
+func TestCheckFontSizeOverrides(t *testing.T) {
+	warnings := checkFontSizeOverrides(fsCheckASS)
+	if len(warnings) != 1 {
+		t.Fatalf("harus tepat 1 peringatan, dapat %q", warnings)
+	}
+	if !strings.Contains(warnings[0], `\fs200`) || !strings.Contains(warnings[0], "5.0x") {
+		t.Errorf("peringatan tidak menyebut \\fs200 5x: %q", warnings[0])
+	}
+}
+
+// ======================================
+// 🔹 Parser SRT
+// ======================================
+
+// Teks cue yang kebetulan berupa timestamp (jam di layar) atau angka polos
+// tidak boleh dianggap baris timing / nomor cue.
+func TestSRTTimestampText(t *testing.T) {
+	withOpts(t)
+	srt := "1\n00:00:01,000 --> 00:00:03,000\n00:00:05,000\n\n" +
+		"2\n00:00:04,000 --> 00:00:06,000\nSkor akhir\n42\n\n" +
+		"3\n00:00:07,000 --> 00:00:08,000\nSelesai\n"
+	cues := scanSRTCues(srt)
+	if len(cues) != 3 {
+		t.Fatalf("harus 3 cue, dapat %d: %+v", len(cues), cues)
+	}
+	if got := strings.Join(cues[0].Text, "|"); got != "00:00:05,000" {
+		t.Errorf("teks cue 1 = %q", got)
+	}
+	if got := strings.Join(cues[1].Text, "|"); got != "Skor akhir|42" {
+		t.Errorf("teks cue 2 = %q", got)
+	}
+	if cues[2].No != 3 {
+		t.Errorf("nomor cue 3 = %d", cues[2].No)
+	}
+
+	lines := dialogues(processSRT(srt))
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "00:00:05,000") {
+		t.Errorf("output ASS salah:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// ======================================
+// 🔹 WebVTT
+// ======================================
+
+func TestVTTCueSettings(t *testing.T) {
+	tests := []struct {
+		settings string
+		want     string
+	}{
+		{"line:0", `{\an8}`},
+		{"line:10%", `{\an8}`},
+		{"line:90%", ""},
+		{"align:start", `{\an1}`},
+		{"line:0 align:end", `{\an9}`},
+		{"position:50% line:10%", `{\an8\pos(960,108)}`},
+		{"line:50% align:left", `{\an4}`},
+	}
+	for _, tt := range tests {
+		if got := vttCueSettingsToASS(strings.Fields(tt.settings)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.settings, got, tt.want)
+		}
+	}
+}
+
+// Cue dengan line:0 harus berakhir di style "Default Above" setelah processSRT.
+func TestVTTTopCueStyle(t *testing.T) {
+	withOpts(t)
+	vtt := "WEBVTT\n\n00:00:01.000 --> 00:00:02.000 line:0\natas\n\n00:00:03.000 --> 00:00:04.000 line:90%\nbawah\n"
+	srt, err := vttToSRT([]byte(vtt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := dialogues(processSRT(srt))
+	if len(lines) != 2 {
+		t.Fatalf("harus 2 dialog: %q", lines)
+	}
+	if !strings.Contains(lines[0], ",Default Above,") || !strings.Contains(lines[1], ",Default,") {
+		t.Errorf("style salah:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// ======================================
+// 🔹 -benchmark
+// ======================================
+
+func TestBenchmarkPhases(t *testing.T) {
+	for _, fixture := range []string{"basic.srt", "basic720.ass"} {
+		t.Run(fixture, func(t *testing.T) {
+			withOpts(t)
+			input := filepath.Join(t.TempDir(), fixture)
+			if err := os.WriteFile(input, readFixture(t, fixture), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			bench := &phaseTimer{}
+			if res := convertOne(input, bench); res.Err != nil {
+				t.Fatal(res.Err)
+			}
+			report := bench.report()
+			for _, phase := range []string{"parse", "transform", "serialize", "total"} {
+				if !strings.Contains(report, phase) {
+					t.Errorf("laporan -benchmark tidak memuat fase %q:\n%s", phase, report)
+				}
+			}
+			if got := strings.Join(bench.names, ","); got != "parse,transform,serialize" {
+				t.Errorf("urutan fase = %s", got)
+			}
+		})
+	}
+}
+
+func TestVTTNonSubtitleTrack(t *testing.T) {
+	chapters := "WEBVTT - Chapters\n\n1\n00:00:00.000 --> 00:05:00.000\nOpening\n"
+	if _, err := vttToSRT([]byte(chapters)); err == nil || !strings.Contains(err.Error(), "chapters") {
+		t.Errorf("track chapters harus ditolak, err = %v", err)
+	}
+	kindField := "WEBVTT\nKind: metadata\n\n00:00:00.000 --> 00:00:01.000\n{\"id\":1}\n"
+	if _, err := vttToSRT([]byte(kindField)); err == nil {
+		t.Error("Kind: metadata harus ditolak")
+	}
+
+	// kata "chapters"/"metadata" di teks bebas header bukan jenis track
+	for _, header := range []string{"WEBVTT - no chapters metadata here", "WEBVTT subtitles with descriptions"} {
+		vtt := header + "\n\n00:00:01.000 --> 00:00:02.000\nhalo\n"
+		if _, err := vttToSRT([]byte(vtt)); err != nil {
+			t.Errorf("%q: subtitle biasa ditolak: %v", header, err)
+		}
+	}
+}
+
+// ======================================
+// 🔹 Merge cue processSRT
+// ======================================
+
+// mergeDialogsQuadratic: loop bersarang lama dari processSRT, disimpan sebagai
+// acuan. mergeDialogs harus memberi hasil yang sama persis.
+func mergeDialogsQuadratic(dialogs []srtDialog, mergeGapMs int) []srtDialog {
+	d := append([]srtDialog(nil), dialogs...)
+	sort.Slice(d, func(i, j int) bool {
+		if d[i].Start == d[j].Start {
+			if d[i].End == d[j].End {
+				return d[i].Style < d[j].Style
+			}
+			return d[i].End < d[j].End
+		}
+		return d[i].Start < d[j].Start
+	})
+	withinGap := func(end, start string) bool {
+		if end == start {
+			return true
+		}
+		gap := assTimeToMs(start) - assTimeToMs(end)
+		return mergeGapMs > 0 && gap >= 0 && gap < mergeGapMs
+	}
+	var merged []srtDialog
+	for i := 0; i < len(d); i++ {
+		curr := d[i]
+		for j := i + 1; j < len(d); j++ {
+			next := d[j]
+			if curr.Style == next.Style && curr.Start == next.Start && curr.End == next.End {
+				if curr.Text != next.Text {
+					curr.Text = joinASSLines(curr.Text, next.Text)
+					curr.Source = joinSources(curr.Source, next.Source)
+				}
+				d[j].Style = "__merged__"
+			} else if curr.Style == next.Style && curr.Text == next.Text && withinGap(curr.End, next.Start) {
+				curr.End = next.End
+				curr.Source = joinSources(curr.Source, next.Source)
+				d[j].Style = "__merged__"
+			}
+		}
+		if curr.Style != "__merged__" {
+			merged = append(merged, curr)
+		}
+	}
+	return merged
+}
+
+// randomDialogs membuat cue acak dengan banyak tabrakan (waktu, teks, style
+// yang sama) supaya kedua aturan merge sering terpicu.
+func randomDialogs(rng *rand.Rand, n int) []srtDialog {
+	texts := []string{"halo", "apa kabar", "baik", "halo\\Napa kabar"}
+	styles := []string{"Default", "tanda"}
+	var out []srtDialog
+	for i := 0; i < n; i++ {
+		start := rng.Intn(n/2+1) * 500
+		end := start + (rng.Intn(3)+1)*500
+		out = append(out, srtDialog{
+			Start:  msToASSTime(start),
+			End:    msToASSTime(end),
+			Style:  styles[rng.Intn(len(styles))],
+			Text:   texts[rng.Intn(len(texts))],
+			Source: fmt.Sprintf("#%d", i+1),
+		})
+	}
+	return out
+}
+
+func TestMergeDialogsMatchesQuadratic(t *testing.T) {
+	fixture := []srtDialog{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "baris satu", Source: "#1"},
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "baris dua", Source: "#2"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "sama", Source: "#3"},
+		{Start: "0:00:03.00", End: "0:00:04.00", Style: "Default", Text: "sama", Source: "#4"},
+		{Start: "0:00:04.05", End: "0:00:05.00", Style: "Default", Text: "sama", Source: "#5"},
+		{Start: "0:00:03.00", End: "0:00:04.00", Style: "tanda", Text: "sama", Source: "#6"},
+		{Start: "0:00:06.00", End: "0:00:07.00", Style: "Default", Text: "dobel", Source: "#7"},
+		{Start: "0:00:06.00", End: "0:00:07.00", Style: "Default", Text: "dobel", Source: "#8"},
+	}
+	rng := rand.New(rand.NewSource(1))
+	cases := [][]srtDialog{fixture}
+	for i := 0; i < 200; i++ {
+		cases = append(cases, randomDialogs(rng, 2+rng.Intn(30)))
+	}
+	for i, dialogs := range cases {
+		for _, gap := range []int{0, 100} {
+			want := mergeDialogsQuadratic(dialogs, gap)
+			got := mergeDialogs(append([]srtDialog(nil), dialogs...), gap)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("kasus %d (gap %d) beda:\ngot  %+v\nwant %+v", i, gap, got, want)
+			}
+		}
+	}
+}
+
+// benchSRT: SRT mesin dengan n cue, sebagian berulang supaya merge bekerja.
+func benchSRT(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		start := float64(i) * 1.5
+		fmt.Fprintf(&sb, "%d\n%s --> %s\nkalimat nomor %d\n\n", i+1, formatTime(start), formatTime(start+1.5), i/3)
+	}
+	return sb.String()
+}
+
+func BenchmarkProcessSRT5000(b *testing.B) {
+	srt := benchSRT(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		processSRT(srt)
+	}
+}
+
+func BenchmarkMergeDialogs5000(b *testing.B) {
+	dialogs := randomDialogs(rand.New(rand.NewSource(1)), 5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mergeDialogs(append([]srtDialog(nil), dialogs...), 0)
+	}
+}
+
+// ======================================
+// 🔹 Import cue sheet (-timing)
+// ======================================
+
+// Ekspor ASS ke CSV, sunting teksnya (dan timing di sheet, yang harus
+// diabaikan), lalu import balik dengan timing asli.
+func TestCueSheetRoundTripWithTiming(t *testing.T) {
+	withOpts(t)
+	orig := string(readFixture(t, "basic720.ass"))
+	exported := writeCueSheet(parseASSCues(orig), ',')
+
+	rows, err := csv.NewReader(strings.NewReader(exported)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows[1][4] = "Hello everyone" // text
+	rows[1][1] = "00:09:99.000"   // start di sheet diabaikan
+	rows[2][4] = "Sign\nline two"
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.WriteAll(rows)
+
+	got, err := cueSheetOntoASS(orig, strings.NewReader(sb.String()), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dialogues(orig)
+	want[0] = "Dialogue: 0,0:00:01.00,0:00:03.00,Default,,0,0,0,,Hello everyone"
+	want[1] = `Dialogue: 0,0:00:04.00,0:00:05.00,Default,,10,10,20,,{\pos(640,360)\fs48\bord2\frz-30}Sign\Nline two`
+	if g := dialogues(got); !reflect.DeepEqual(g, want) {
+		t.Errorf("hasil import:\n%s\nwant:\n%s", strings.Join(g, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(got, "YCbCr Matrix: TV.601") {
+		t.Error("header file asal harus dipertahankan")
+	}
+
+	// sheet yang lebih panjang dari file timing berarti file timing-nya salah
+	long := "index,text\n1,a\n9,b\n"
+	if _, err := cueSheetOntoASS(orig, strings.NewReader(long), ','); err == nil {
+		t.Error("index di luar jumlah Dialogue harus error")
+	}
+}
+
+func TestConvertCueSheetWithTimingFlag(t *testing.T) {
+	withOpts(t)
+	dir := t.TempDir()
+	timing := filepath.Join(dir, "episode.ass")
+	sheet := filepath.Join(dir, "episode.tsv")
+	os.WriteFile(timing, readFixture(t, "basic720.ass"), 0o644)
+	os.WriteFile(sheet, []byte("index\ttext\n3\tMoving\n"), 0o644)
+
+	opts.Timing = timing
+	got, _, err := convertFile(sheet, &phaseTimer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := dialogues(got)
+	if len(lines) != 4 || !strings.HasSuffix(lines[2], `,{\move(0,0,100,100,500,1500)\clip(m 10 10 l 10 20 20 20)}Moving`) {
+		t.Errorf("baris 3 harus diganti:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// ======================================
+// 🔹 Resampler ASS
+// ======================================
+
+// miniASS: script ASS kecil di srcW×srcH dengan satu baris Style: dan
+// baris-baris Dialogue: yang diberikan.
+func miniASS(srcW, srcH int, style string, events ...string) string {
+	return fmt.Sprintf(`[Script Info]
+ScriptType: v4.00+
+PlayResX: %d
+PlayResY: %d
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+%s
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+%s
+`, srcW, srcH, style, strings.Join(events, "\n"))
+}
+
+const miniStyle = "Style: Default,Arial,40,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,2,20,20,30,1"
+
+// resampleEvent me-resample satu event dari srcW×srcH ke 1920×1080 dan
+// mengembalikan baris Dialogue hasilnya. event boleh baris Dialogue: lengkap
+// atau hanya teks.
+func resampleEvent(t *testing.T, srcW, srcH int, event string) string {
+	t.Helper()
+	if !strings.HasPrefix(event, "Dialogue:") {
+		event = "Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,," + event
+	}
+	out, err := ResampleASS(miniASS(srcW, srcH, miniStyle, event), 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := dialogues(out)
+	if len(lines) != 1 {
+		t.Fatalf("harus 1 Dialogue, dapat %q", lines)
+	}
+	return lines[0]
+}
+
+// \pos dari 720p ke 1080p harus selalu tepat dan berformat sama, lewat
+// ResampleASS maupun resampleLimenime (jalur drag & drop).
+func TestResamplePosDeterminism(t *testing.T) {
+	withOpts(t)
+	tests := []struct{ in, want string }{
+		{`{\pos(640,360)}x`, `{\pos(960,540)}x`},
+		{`{\pos(640.0,360.00)}x`, `{\pos(960,540)}x`},
+		{`{\pos(100.5,33.3)}x`, `{\pos(150.75,49.95)}x`},
+		{`{\pos(-10,0)}x`, `{\pos(-15,0)}x`},
+	}
+	for _, tt := range tests {
+		got := dialogueText(resampleEvent(t, 1280, 720, tt.in))
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	out, err := resampleLimenime(string(readFixture(t, "basic720.ass")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `\pos(960,540)`) {
+		t.Errorf("resampleLimenime: \\pos(640,360) tidak jadi \\pos(960,540)")
+	}
+}
+
+// Bilibili dengan location campuran: atas → Default Above, bawah/tanpa
+// location → Default, caption kosong (setelah tag dibuang) dilewati dan tidak
+// menyisakan cue {\anN} saja.
+func TestBilibiliMixedLocation(t *testing.T) {
+	withOpts(t)
+	data := []byte(`{"body": [
+		{"from": 1, "to": 2, "location": 2, "content": "Bawah"},
+		{"from": 3, "to": 4, "location": 8, "content": "Atas"},
+		{"from": 5, "to": 6, "location": 8, "content": "  "},
+		{"from": 7, "to": 8, "location": 7, "content": "<b></b>"},
+		{"from": 9, "to": 10, "content": "Tanpa location"},
+		{"from": 11, "to": 12, "location": 1, "content": "Kiri bawah"}
+	]}`)
+	srt, err := jsonToSRT(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(srt, "{\\an8}\n") || strings.Contains(srt, "{\\an7}<b>") {
+		t.Errorf("cue kosong masih membawa tag posisi:\n%s", srt)
+	}
+	lines := dialogues(processSRT(srt))
+	type cue struct{ style, text string }
+	want := []cue{
+		{"Default", "Bawah"},
+		{"Default Above", `{\an8}Atas`},
+		{"Default", "Tanpa location"},
+		{"Default", `{\an1}Kiri bawah`},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("harus %d Dialogue, dapat %d:\n%s", len(want), len(lines), strings.Join(lines, "\n"))
+	}
+	for i, w := range want {
+		style := splitNPreserveTrailing(lines[i], ',', 10)[3]
+		if style != w.style || !strings.HasSuffix(dialogueText(lines[i]), w.text) {
+			t.Errorf("cue %d: got %s / %q, want %s / %q", i+1, style, dialogueText(lines[i]), w.style, w.text)
+		}
+	}
+}
+
+// Deteksi JSON harus struktural dan deterministik: kata "body"/"events" di
+// dalam caption tidak berpengaruh, dan objek dengan kedua kunci selalu bilibili.
+func TestDetectJSONFormat(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{"body":[{"from":1,"to":2,"content":"events di body"}]}`, "bilibili"},
+		{`{"events":[{"tStartMs":0,"segs":[{"utf8":"body di events"}]}]}`, "youtube"},
+		{`{"Events":[],"BODY":[]}`, "bilibili"},
+		{`{"events":[],"body":"bukan array"}`, "youtube"},
+		{`{"events":[],"body":[]}`, "bilibili"},
+		{`[{"start":1,"end":2,"text":"body events"}]`, "flat"},
+		{`{"text":"body events"}`, ""},
+	}
+	for _, tt := range tests {
+		// ulangi supaya urutan iterasi map yang acak ikut teruji
+		for i := 0; i < 20; i++ {
+			got, err := detectJSONFormat([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%s: got %q, want %q", tt.in, got, tt.want)
+			}
+		}
+	}
+}
+
+// Potongan yang sudah diakhiri/diawali pemisah (\N atau \n) tidak boleh
+// menghasilkan baris kosong saat digabung.
+func TestJoinASSLines(t *testing.T) {
+	withOpts(t)
+	tests := []struct{ a, b, want string }{
+		{"Halo", "Dunia", `Halo\NDunia`},
+		{`Halo\N`, "Dunia", `Halo\NDunia`},
+		{`Halo\n`, "Dunia", `Halo\NDunia`},
+		{`Halo \N\n `, `\nDunia`, `Halo\NDunia`},
+		{`A\N\NB`, `C\n\nD`, `A\NB\NC\nD`},
+		{`A\n\NB`, "C", `A\NB\NC`},
+		{`\N`, "Dunia", "Dunia"},
+		{"Halo", `\n`, "Halo"},
+	}
+	for _, tt := range tests {
+		if got := joinASSLines(tt.a, tt.b); got != tt.want {
+			t.Errorf("joinASSLines(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// Aturan skala ala Aegisub: Fontsize style → ry (int), ScaleX/\fscx → ar,
+// Outline/Shadow style → ry, \bord/\shad/\blur → rm, \xbord → rx, \ybord → ry,
+// margin style dan event → rx/ry (int).
+func TestResampleRules(t *testing.T) {
+	style := "Style: Default,Arial,41,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,2,0,1,2,1,2,21,20,31,1"
+	event := `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,10,10,20,,{\fs20\fscx100\fscy100\bord2\xbord2\ybord2\shad1\blur1\fsp2\pos(100,100)}x`
+	tests := []struct {
+		name      string
+		w, h      int
+		wantStyle string
+		wantEvent string
+	}{
+		{
+			"1080p", 1920, 1080,
+			"Style: Default,Arial,62,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,3,0,1,3,1.5,2,32,30,47,1",
+			`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,15,15,30,,{\fs30\fscx100\fscy100\bord3\xbord3\ybord3\shad1.5\blur1.5\fsp3\pos(150,150)}x`,
+		},
+		{
+			// anamorfik 4:3: rx=1.125, ry=1.5, rm=√(rx·ry)≈1.299, ar=0.75
+			"1440x1080", 1440, 1080,
+			"Style: Default,Arial,62,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,75,100,2.25,0,1,3,1.5,2,24,23,47,1",
+			`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,11,11,30,,{\fs30\fscx75\fscy100\bord2.598\xbord2.25\ybord3\shad1.299\blur1.299\fsp2.25\pos(112.5,150)}x`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ResampleASS(miniASS(1280, 720, style, event), tt.w, tt.h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.wantStyle+"\n") {
+				t.Errorf("Style salah, want %s\n%s", tt.wantStyle, out)
+			}
+			if got := dialogues(out); len(got) != 1 || got[0] != tt.wantEvent {
+				t.Errorf("Dialogue:\n got %q\nwant %q", got, tt.wantEvent)
+			}
+		})
+	}
+
+	// resolusi sama: tidak ada angka yang dinormalkan
+	src := miniASS(1920, 1080, style, event)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != src {
+		t.Errorf("resample ke resolusi yang sama mengubah isi:\n%s", out)
+	}
+}
+
+// \org dengan spasi dan bentuk 3-argumen: hanya x,y yang diskalakan.
+func TestResampleOrg(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{\org( 960 , 540 )\frz10}x`, `{\org(1440,810)\frz10}x`},
+		{`{\org(10,20,30)}x`, `{\org(15,30,30)}x`},
+	}
+	for _, tt := range tests {
+		if got := dialogueText(resampleEvent(t, 1280, 720, tt.in)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// Format [Events] tanpa kolom Text (diakhiri Effect): baris event dibiarkan
+// apa adanya, kolom terakhir tidak diperlakukan sebagai teks.
+func TestResampleEventsFormatWithoutText(t *testing.T) {
+	event := `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,10,10,20,{\pos(640,360)}`
+	src := strings.Replace(miniASS(1280, 720, miniStyle, event),
+		"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text",
+		"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect", 1)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dialogues(out); len(got) != 1 || got[0] != event {
+		t.Errorf("got %q, want %q", got, event)
+	}
+}
+
+// Urutan kolom [Events] Format dibaca dari barisnya: margin dan teks tetap
+// dikenali walau Style/Name dipindah.
+func TestResampleReorderedEventsFormat(t *testing.T) {
+	event := `Dialogue: 0,Default,0:00:01.00,0:00:02.00,10,10,20,,x,{\pos(640,360)\fs40}Halo`
+	src := strings.Replace(miniASS(1280, 720, miniStyle, event),
+		"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text",
+		"Format: Layer, Style, Start, End, MarginL, MarginR, MarginV, Name, Effect, Text", 1)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Dialogue: 0,Default,0:00:01.00,0:00:02.00,15,15,30,,x,{\pos(960,540)\fs60}Halo`
+	if got := dialogues(out); len(got) != 1 || got[0] != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Baris Comment: di-resample sama seperti Dialogue (posisi, margin, \fs).
+func TestResampleCommentLine(t *testing.T) {
+	src := miniASS(1280, 720, miniStyle, `Comment: 0,0:00:01.00,0:00:02.00,Default,,10,10,20,,{\pos(640,360)\fs40}Catatan`)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Comment: 0,0:00:01.00,0:00:02.00,Default,,15,15,30,,{\pos(960,540)\fs60}Catatan`
+	if got := dialogues(out); len(got) != 1 || got[0] != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// [Aegisub Project Garbage] di akhir file disalin byte demi byte.
+func TestResampleKeepsProjectGarbage(t *testing.T) {
+	garbage := "\n[Aegisub Project Garbage]\nAudio File: a.mkv\nScroll Position: 12\nActive Line: 3\nVideo Zoom Percent: 0.5\nVideo Position: 100\n"
+	out, err := ResampleASS(miniASS(1280, 720, miniStyle, `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\pos(640,360)}x`)+garbage, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out, garbage) {
+		t.Errorf("garbage berubah:\n%s", out)
+	}
+}
+
+// Blok [Fonts] (uuencode) disalin apa adanya di antara Styles dan Events.
+func TestResampleKeepsFontsBlock(t *testing.T) {
+	fonts := "\n[Fonts]\nfontname: a_0.ttf\nM9F]N9&%T97-T!\"$]\"!@,#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJ\n\n[Events]\n"
+	src := strings.Replace(miniASS(1280, 720, miniStyle, `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,x`), "\n[Events]\n", fonts, 1)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, fonts) {
+		t.Errorf("[Fonts] berubah:\n%s", out)
+	}
+	if got := dialogues(out); len(got) != 1 {
+		t.Errorf("events: %q", got)
+	}
+}
+
+// \alpha dan \1a tidak diskalakan walau satu blok dengan \bord.
+func TestResampleKeepsAlphaTags(t *testing.T) {
+	got := resampleEvent(t, 1280, 720, `{\alpha&HFF&\1a&H80&\bord2}x`)
+	if want := `{\alpha&HFF&\1a&H80&\bord3}x`; dialogueText(got) != want {
+		t.Errorf("got %q, want %q", dialogueText(got), want)
+	}
+}
+
+// \pos berspasi dan \pos dengan argumen ketiga: dua koordinat pertama tetap diskalakan.
+func TestResamplePosVariants(t *testing.T) {
+	for in, want := range map[string]string{
+		`{\pos( 640 , 360 )}a`: `{\pos(960,540)}a`,
+		`{\pos(640,360,0)}b`:   `{\pos(960,540,0)}b`,
+	} {
+		if got := dialogueText(resampleEvent(t, 1280, 720, in)); got != want {
+			t.Errorf("%s: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// \kt, \k dan argumen waktu \fade adalah waktu, bukan ukuran: tidak diskalakan.
+func TestResampleKeepsTimingTags(t *testing.T) {
+	got := dialogueText(resampleEvent(t, 1280, 720, `{\kt0\k20\fade(255,0,255,0,100,200,300)\fs40}c`))
+	if want := `{\kt0\k20\fade(255,0,255,0,100,200,300)\fs60}c`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Suffix koordinat X1..Y2 di baris timing SRT: bukan timing rusak, diabaikan
+// secara default, jadi \an7\pos dengan -srt-coords, dan dipertahankan -to srt.
+func TestSRTCoordsSuffix(t *testing.T) {
+	withOpts(t)
+	srt := "1\n00:00:01,000 --> 00:00:02,000  X1:100 X2:500 Y1:50 Y2:90\nHalo\n"
+	out, report := processSRTReport([]byte(srt))
+	if len(report.BadTiming) != 0 {
+		t.Errorf("suffix dianggap timing rusak: %v", report.BadTiming)
+	}
+	if lines := dialogues(out); len(lines) != 1 || !strings.HasPrefix(lines[0], "Dialogue: 0,0:00:01.00,0:00:02.00,") || strings.Contains(lines[0], `\pos`) {
+		t.Errorf("default: got %q", lines)
+	}
+	if clean, _ := cleanSRT(srt); !strings.Contains(clean, "00:00:01,000 --> 00:00:02,000 X1:100 X2:500 Y1:50 Y2:90\n") {
+		t.Errorf("-to srt: got %q", clean)
+	}
+	opts.SRTCoords = true
+	if lines := dialogues(processSRT([]byte(srt))); len(lines) != 1 || !strings.HasSuffix(lines[0], `,,{\an7\pos(100,50)}Halo`) {
+		t.Errorf("-srt-coords: got %q", lines)
+	}
+}
+
+// \fsc tetap satu tag bila aspek sama; \fsp negatif ikut diskalakan. Bila aspek
+// berubah (anamorfik), \fsc dipecah jadi \fscx (dikoreksi) + \fscy.
+func TestResampleFscFsp(t *testing.T) {
+	if got := dialogueText(resampleEvent(t, 1280, 720, `{\fsc120\fsp-3}d`)); got != `{\fsc120\fsp-4.5}d` {
+		t.Errorf("aspek sama: got %q", got)
+	}
+	out, err := ResampleASS(miniASS(1280, 720, miniStyle, `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\fsc120\fsp-3}d`), 1440, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dialogueText(dialogues(out)[0]); got != `{\fscx90\fscy120\fsp-3.375}d` {
+		t.Errorf("anamorfik: got %q", got)
+	}
+}
+
+// [Script Info] selain PlayRes disalin apa adanya: matrix warna tidak boleh
+// berubah saat resample.
+func TestResampleKeepsYCbCrMatrix(t *testing.T) {
+	src := strings.Replace(miniASS(1280, 720, miniStyle), "PlayResY: 720\n",
+		"PlayResY: 720\nScaledBorderAndShadow: yes\nYCbCr Matrix: TV.601\n", 1)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"PlayResX: 1920\n", "PlayResY: 1080\n", "ScaledBorderAndShadow: yes\n", "YCbCr Matrix: TV.601\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output tidak berisi %q:\n%s", want, out)
+		}
+	}
+}
+
+// \an8 dengan MarginV: margin diskalakan ry tanpa melihat alignment, jadi jarak
+// relatif dari tepi atas tetap sama (20 @720p → 30 @1080p).
+func TestResampleTopMarginV(t *testing.T) {
+	style := strings.Replace(miniStyle, ",2,20,20,30,1", ",8,20,20,20,1", 1)
+	event := `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,20,,{\an8}Atas`
+	out, err := ResampleASS(miniASS(1280, 720, style, event), 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, ",8,30,30,30,1\n") {
+		t.Errorf("MarginV style \\an8 salah:\n%s", out)
+	}
+	want := `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,30,,{\an8}Atas`
+	if got := dialogues(out); len(got) != 1 || got[0] != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Tag ukuran di 720p → 1080p (rm = 1.5): nol tetap nol, tanda dipertahankan,
+// \be dibulatkan ke int. Saat rm = 1 (rx/ry tetap berubah) \be tidak disentuh.
+func TestResampleSizeTags(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{\bord0}x`, `{\bord0}x`},
+		{`{\shad-2}x`, `{\shad-3}x`},
+		{`{\blur0.5}x`, `{\blur0.75}x`},
+		{`{\be1}x`, `{\be2}x`},
+		{`{\be0}x`, `{\be0}x`},
+	}
+	for _, tt := range tests {
+		if got := dialogueText(resampleEvent(t, 1280, 720, tt.in)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	// 1280x720 → 720x1280: rx·ry = 1, jadi rm = 1
+	out, err := ResampleASS(miniASS(1280, 720, miniStyle, `Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\be1.5\bord2}x`), 720, 1280)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dialogueText(dialogues(out)[0]); got != `{\be1.5\bord2}x` {
+		t.Errorf("rm = 1: got %s, want {\\be1.5\\bord2}x", got)
+	}
+}
+
+// -crlf/-bom: tanpa flag mengikuti format output (ASS: BOM+CRLF, SRT: CRLF),
+// flag eksplisit menang di kedua arah.
+func TestLineEndingFlags(t *testing.T) {
+	const in = "\ufeffa\nb\r\nc\n"
+	tests := []struct {
+		args   []string
+		format string
+		want   string
+	}{
+		{nil, "ass", "\ufeffa\r\nb\r\nc\r\n"},
+		{nil, "srt", "a\r\nb\r\nc\r\n"},
+		{nil, "vtt", "a\nb\nc\n"},
+		{[]string{"-crlf=false", "-bom=false"}, "ass", "a\nb\nc\n"},
+		{[]string{"-crlf=false"}, "ass", "\ufeffa\nb\nc\n"},
+		{[]string{"-crlf", "-bom"}, "vtt", "\ufeffa\r\nb\r\nc\r\n"},
+	}
+	for _, tt := range tests {
+		withOpts(t)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		finish := lineEndingFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		finish(tt.format)
+		if got := applyLineEndings(in); got != tt.want {
+			t.Errorf("%v %s: got %q, want %q", tt.args, tt.format, got, tt.want)
+		}
+	}
+}
+
+// Sudut rotasi dalam derajat, jadi \frz/\frx lolos byte demi byte sementara
+// \pos dan \fs di blok yang sama ikut diskalakan.
+func TestResampleKeepsRotation(t *testing.T) {
+	got := dialogueText(resampleEvent(t, 1280, 720, `{\pos(640,360)\frz-30\fs48\frx10}x`))
+	if want := `{\pos(960,540)\frz-30\fs72\frx10}x`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// \pbo (offset baseline drawing) ikut ry seperti koordinat drawing-nya.
+func TestResampleDrawingBaselineOffset(t *testing.T) {
+	got := dialogueText(resampleEvent(t, 1280, 720, `{\p1\pbo10}m 0 0 l 10 10{\p0}`))
+	if want := `{\p1\pbo15}m 0 0 l 15 15{\p0}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// \move: hanya koordinat yang diskalakan, t1/t2 (ms) tetap.
+func TestResampleMoveKeepsTimes(t *testing.T) {
+	got := dialogueText(resampleEvent(t, 1280, 720, `{\move(0,0,100,100,500,1500)}x`))
+	if want := `{\move(0,0,150,150,500,1500)}x`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// ======================================
+// 🔹 Config (limesub.json)
+// ======================================
+
+const sampleConfig = `{
+  "font": "Roboto",
+  "resolution": "1920x1080",
+  "styles": {
+    "Zeta": "Style: Zeta,Arial,50,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,2,10,10,10,1",
+    "Alpha": "Alpha,Arial,40,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,8,10,10,10,1"
+  },
+  "flags": {"mergegap": "100ms"},
+  "header_file": "header.ass"
+}`
+
+// loadConfig dari direktori kerja; style tambahan masuk tepat setelah Style:
+// terakhir di [V4+ Styles], baik diikuti baris kosong, langsung header
+// berikutnya, atau EOF.
+func TestLoadConfigAndHeaderStyles(t *testing.T) {
+	withOpts(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, configFileName), []byte(sampleConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	c, path, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != configFileName {
+		t.Errorf("path = %q, want %q", path, configFileName)
+	}
+	if c.Font != "Roboto" || c.Resolution != "1920x1080" || c.Flags["mergegap"] != "100ms" {
+		t.Errorf("config salah: %+v", c)
+	}
+	if c.HeaderFile != filepath.Join(".", "header.ass") {
+		t.Errorf("header_file = %q", c.HeaderFile)
+	}
+	if !strings.HasPrefix(c.Styles["Alpha"], "Style: Alpha,") {
+		t.Errorf("style tanpa prefix tidak dinormalkan: %q", c.Styles["Alpha"])
+	}
+
+	def := "Style: Default,Arial,40,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,2,20,20,30,1"
+	tests := []struct{ name, template string }{
+		{"baris kosong", "[V4+ Styles]\nFormat: Name\n" + def + "\n\n[Events]\nFormat: Text"},
+		{"tanpa baris kosong", "[V4+ Styles]\nFormat: Name\n" + def + "\n[Events]\nFormat: Text"},
+		{"EOF", "[Script Info]\nTitle: x\n\n[V4+ Styles]\nFormat: Name\n" + def},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assHeaderTemplate = tt.template
+			lines := strings.Split(c.assHeader(), "\n")
+			at := -1
+			for i, l := range lines {
+				if strings.HasPrefix(l, "Style: Default,") {
+					at = i
+				}
+			}
+			if at < 0 || at+2 >= len(lines) {
+				t.Fatalf("Default hilang atau style tambahan tidak disisipkan:\n%s", strings.Join(lines, "\n"))
+			}
+			if !strings.HasPrefix(lines[at], "Style: Default,Roboto,") {
+				t.Errorf("font config tidak dipakai: %s", lines[at])
+			}
+			if !strings.HasPrefix(lines[at+1], "Style: Alpha,") || !strings.HasPrefix(lines[at+2], "Style: Zeta,") {
+				t.Errorf("style tambahan tidak tepat setelah Default:\n%s", strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+// ======================================
+// 🔹 Style song
+// ======================================
+
+// Lirik (♪/♫ di awal atau diapit #) masuk style song; # di awal saja tidak.
+// Template header tanpa style song membuat lirik jatuh ke Default.
+func TestSongStyle(t *testing.T) {
+	withOpts(t)
+	tests := []struct {
+		text string
+		song bool
+	}{
+		{"♪ lyric ♪", true},
+		{"♫ Sakura mau", true},
+		{`{\i1}♪ lirik miring ♪{\i0}`, true},
+		{"# lirik lagu #", true},
+		{"#1 di dunia", false},
+		{"#tagar", false},
+		{"# #", false},
+		{"Biasa saja", false},
+	}
+	for _, tt := range tests {
+		if got := isSongCue(tt.text); got != tt.song {
+			t.Errorf("isSongCue(%q) = %v, want %v", tt.text, got, tt.song)
+		}
+	}
+
+	srt := "1\n00:00:01,000 --> 00:00:03,000\n♪ lyric ♪\n\n2\n00:00:04,000 --> 00:00:06,000\n#1 di dunia\n\n"
+	styles := func() []string {
+		var out []string
+		for _, l := range dialogues(processSRT(srt)) {
+			out = append(out, splitNPreserveTrailing(l, ',', 10)[3])
+		}
+		return out
+	}
+	if got := styles(); !reflect.DeepEqual(got, []string{"song", "Default"}) {
+		t.Errorf("header bawaan: got %q, want [song Default]", got)
+	}
+
+	var custom []string
+	for _, l := range strings.Split(limenimeASSHeader, "\n") {
+		if !strings.HasPrefix(l, "Style: song,") {
+			custom = append(custom, l)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "header.ass")
+	if err := os.WriteFile(path, []byte(strings.Join(custom, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	header, err := loadHeaderTemplate(path)
+	if err != nil {
+		t.Fatalf("template tanpa song ditolak: %v", err)
+	}
+	assHeaderTemplate = header
+	if got := styles(); !reflect.DeepEqual(got, []string{"Default", "Default"}) {
+		t.Errorf("template tanpa song: got %q, want [Default Default]", got)
+	}
+}
+
+// ======================================
+// 🔹 Benchmark resample
+// ======================================
+
+// benchASS: script 720p dengan n baris Dialogue berisi campuran tag yang
+// diskalakan, tag yang dilewati (alpha, karaoke, rotasi) dan teks biasa.
+func benchASS(n int) string {
+	events := make([]string, n)
+	for i := range events {
+		events[i] = fmt.Sprintf(`Dialogue: 0,0:00:%02d.00,0:00:%02d.50,Default,,10,10,20,,{\pos(%d,360)\fs48\bord2\shad1\blur0.5\frz-30\1a&H80&}kalimat {\k20}nomor{\k30} %d`,
+			i%60, i%60, i%1280, i)
+	}
+	return miniASS(1280, 720, miniStyle, events...)
+}
+
+// Regex resampler & konversi tag dikompilasi sekali di level package; jalankan
+// dengan -bench dan bandingkan dengan commit sebelumnya untuk angka before/after.
+func BenchmarkResampleASS5000(b *testing.B) {
+	ass := benchASS(5000)
+	b.SetBytes(int64(len(ass)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ResampleASS(ass, 1920, 1080); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvertTagsToASS5000(b *testing.B) {
+	lines := make([]string, 5000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(`<i>kalimat</i> <font color="#ff0000">nomor</font> {\an8}<b>%d</b>`, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, l := range lines {
+			convertTagsToASS(l)
+		}
+	}
+}
+
+// scalePathRegex: scalePathAt lama berbasis regex, disimpan sebagai acuan.
+// Pemindai manual harus memberi hasil yang sama persis.
+func scalePathRegex(r resampler, path string) string {
+	i := 0
+	return regexp.MustCompile(`[A-Za-z]|-?\d*\.?\d+`).ReplaceAllStringFunc(path, func(m string) string {
+		if unicode.IsLetter(rune(m[0])) {
+			i = 0
+			return m
+		}
+		k := r.rx
+		if i%2 == 1 {
+			k = r.ry
+		}
+		i++
+		out := r.scale(m, k)
+		if m[0] == '-' && out[0] != '-' {
+			out = " " + out
+		}
+		return out
+	})
+}
+
+func TestScalePathMatchesRegex(t *testing.T) {
+	r := resampler{rx: 1.5, ry: 1.25, rm: math.Sqrt(1.5 * 1.25), ar: 1.2}
+	fixed := []string{
+		"m 0 0 l 100 0 100 100", "m -0.4 10-0.2 l 1.2.3 4..5 6.", "b 1 2 3 4 5 6 c",
+		"m 10 10 l 10 20", "-", "--1", ".5 -.5 1e3", "ｍ 10 20 é 3", "",
+	}
+	const alphabet = "0123456789.- mlbcspn\\xé"
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 2000; n++ {
+		b := make([]rune, rng.Intn(24))
+		for i := range b {
+			b[i] = []rune(alphabet)[rng.Intn(len([]rune(alphabet)))]
+		}
+		fixed = append(fixed, string(b))
+	}
+	for _, p := range fixed {
+		if got, want := r.scalePath(p), scalePathRegex(r, p); got != want {
+			t.Fatalf("scalePath(%q) = %q, regex lama %q", p, got, want)
+		}
+	}
+}
+
+// \clip/\iclip dicari kepala regex + penutup ")" manual; hasilnya harus sama
+// dengan regex penuh lama, termasuk tag yang tidak ditutup.
+func TestResampleClip(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{\clip(0,0,640,360)}x`, `{\clip(0,0,960,540)}x`},
+		{`{\iclip( 2, m 0 0 l 10 10 )\pos(0,10)}x`, `{\iclip(2, m 0 0 l 15 15)\pos(0,15)}x`},
+		{`{\clip(m 10 10 l 10 20)\clip (0,0,2,2)}x`, `{\clip(m 15 15 l 15 30)\clip(0,0,3,3)}x`},
+		{`{\clip(0,0,640}x`, `{\clip(0,0,640}x`},
+	}
+	for _, tt := range tests {
+		if got := dialogueText(resampleEvent(t, 1280, 720, tt.in)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// Drawing panjang dan vector clip: semua angka diganti dalam satu pass
+// strings.Builder, jadi waktu harus linear terhadap panjang path.
+func BenchmarkResampleDrawing(b *testing.B) {
+	var path strings.Builder
+	path.WriteString("m 0 0")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&path, " l %d %d", i%1280, i%720)
+	}
+	event := fmt.Sprintf(`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\clip(%s)\p1}%s{\p0}`, path.String(), path.String())
+	events := make([]string, 50)
+	for i := range events {
+		events[i] = event
+	}
+	ass := miniASS(1280, 720, miniStyle, events...)
+	b.SetBytes(int64(len(ass)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ResampleASS(ass, 1920, 1080); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Koordinat yang berulang diganti per posisi, bukan per string: tiap angka
+// mendapat rasio sumbunya sendiri walau nilainya sama (rx ≠ ry di 1440x1080).
+func TestResampleRepeatedCoords(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{\clip(m 10 10 l 10 20)}x`, `{\clip(m 11.25 15 l 11.25 30)}x`},
+		{`{\p1}m 10 10 l 10 20{\p0}`, `{\p1}m 11.25 15 l 11.25 30{\p0}`},
+		{`{\p1}m 100 100 l 100 200 150 150{\p0}`, `{\p1}m 112.5 150 l 112.5 300 168.75 225{\p0}`},
+	}
+	for _, tt := range tests {
+		event := "Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,," + tt.in
+		out, err := ResampleASS(miniASS(1280, 720, miniStyle, event), 1440, 1080)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := dialogueText(dialogues(out)[0]); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// { tanpa } di baris yang sama adalah teks biasa: tidak menelan sisa baris dan
+// blok override sebelumnya tetap diskalakan.
+func TestResampleLoneBrace(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{\pos(640,360)}a { b \pos(1,1)`, `{\pos(960,540)}a { b \pos(1,1)`},
+		{`{ hanya kurung`, `{ hanya kurung`},
+		{`teks } lalu {\fs40}x`, `teks } lalu {\fs60}x`},
+	}
+	for _, tt := range tests {
+		if got := dialogueText(resampleEvent(t, 1280, 720, tt.in)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// -linebreak: hard memakai \N, soft memakai \n, untuk cue multi-baris maupun
+// cue yang digabung; ASS-ke-ASS tetap memakai pemisah dari sumber.
+func TestLineBreakModes(t *testing.T) {
+	srt := "1\n00:00:01,000 --> 00:00:03,000\nBaris satu\nBaris dua\n\n" +
+		"2\n00:00:05,000 --> 00:00:07,000\nSama\n\n" +
+		"3\n00:00:05,000 --> 00:00:07,000\nWaktu\n\n"
+	for _, mode := range []struct{ name, br, other string }{
+		{"hard", `\N`, `\n`},
+		{"soft", `\n`, `\N`},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			withOpts(t)
+			opts.LineBreak = mode.name
+			lines := dialogues(processSRT(srt))
+			if len(lines) != 2 {
+				t.Fatalf("harus 2 Dialogue, dapat %q", lines)
+			}
+			for i, want := range []string{"Baris satu" + mode.br + "Baris dua", "Sama" + mode.br + "Waktu"} {
+				text := dialogueText(lines[i])
+				if !strings.HasSuffix(text, want) || strings.Contains(text, mode.other) {
+					t.Errorf("cue %d: got %q, want akhiran %q", i+1, text, want)
+				}
+			}
+
+			ass := strings.Replace(string(readFixture(t, "basic720.ass")), ",,Halo semua", `,,Keras\Ndan\nlunak`, 1)
+			out, err := resampleLimenime(ass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, `Keras\Ndan\nlunak`) {
+				t.Errorf("pemisah sumber ASS berubah:\n%s", out)
+			}
+		})
+	}
+}
+
+// -min-display 1s: cue 200 ms diperpanjang sampai start cue berikutnya (500 ms
+// kemudian) dikurangi minDisplayGapMs; cue terakhir mendapat 1 s penuh; urutan
+// input tidak berpengaruh.
+func TestExtendShortCues(t *testing.T) {
+	srt := "1\n00:00:01,000 --> 00:00:01,200\nKilat\n\n" +
+		"2\n00:00:01,700 --> 00:00:01,900\nBerikutnya\n\n" +
+		"3\n00:00:05,000 --> 00:00:08,000\nPanjang\n"
+	want := "1\n00:00:01,000 --> 00:00:01,620\nKilat\n\n" +
+		"2\n00:00:01,700 --> 00:00:02,700\nBerikutnya\n\n" +
+		"3\n00:00:05,000 --> 00:00:08,000\nPanjang\n"
+	if got := extendShortCues(srt, 1000); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// urutan terbalik: cue berikutnya tetap dicari berdasarkan waktu
+	blocks := strings.Split(srt, "\n\n")
+	wantBlocks := strings.Split(want, "\n\n")
+	rev := blocks[2] + "\n\n" + blocks[1] + "\n\n" + blocks[0] + "\n"
+	wantRev := wantBlocks[2] + "\n\n" + wantBlocks[1] + "\n\n" + wantBlocks[0] + "\n"
+	if got := extendShortCues(rev, 1000); got != wantRev {
+		t.Errorf("input terbalik, got:\n%s\nwant:\n%s", got, wantRev)
+	}
+}
+
+// Kolom Effect (Banner;delay;lefttoright;fadeawaywidth, Scroll up;y1;y2;...)
+// ditulis ulang apa adanya: tidak diskalakan dan tidak hilang.
+func TestResampleKeepsEffect(t *testing.T) {
+	tests := []string{
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,10,10,20,Banner;50;0;0,{\fs40}Lewat`,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,10,10,20,Scroll up;100;600;20;0,Gulir`,
+	}
+	wants := []string{
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,15,15,30,Banner;50;0;0,{\fs60}Lewat`,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,15,15,30,Scroll up;100;600;20;0,Gulir`,
+	}
+	for i, event := range tests {
+		if got := resampleEvent(t, 1280, 720, event); got != wants[i] {
+			t.Errorf("got  %s\nwant %s", got, wants[i])
+		}
+	}
+}
+
+// -to srt: nomor ganda/acak diurutkan ulang, cue kosong dibuang, timestamp
+// ditulis kanonik, dan output SRT default-nya CRLF tanpa BOM.
+func TestCleanSRT(t *testing.T) {
+	withOpts(t)
+	in := "1\r\n00:00:01,000 --> 00:00:02,000\r\nSatu\r\n\r\n" +
+		"1\r\n00:00:03,000 --> 00:00:04,000\r\nDua  \r\n\r\n" +
+		"7\r\n00:00:05,000 --> 00:00:06,000\r\n   \r\n\r\n" +
+		"7\r\n00:00:07,000  -->  00:00:08,500\r\nTiga\r\nbaris dua\r\n\r\n" +
+		"9\r\n00:00:09,000 --> 00:00:10,000\r\n\r\n"
+	want := "1\r\n00:00:01,000 --> 00:00:02,000\r\nSatu\r\n\r\n" +
+		"2\r\n00:00:03,000 --> 00:00:04,000\r\nDua\r\n\r\n" +
+		"3\r\n00:00:07,000 --> 00:00:08,500\r\nTiga\r\nbaris dua\r\n\r\n"
+
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	finish := lineEndingFlags(fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	finish("srt")
+	out, report := cleanSRT(in)
+	if got := applyLineEndings(out); got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+	if report.summary() != "" {
+		t.Errorf("report tidak kosong: %s", report.summary())
+	}
+}
+
+// ======================================
+// 🔹 Fuzz
+// ======================================
+
+// FuzzScaleTags: eventText (skala override + drawing) tidak boleh panic pada
+// input aneh, output selalu UTF-8 valid untuk input valid, dan pada rasio 1
+// teks lolos byte demi byte.
+func FuzzScaleTags(f *testing.F) {
+	for _, seed := range []string{
+		`{\pos(640,360)\fs48\bord2\frz-30}Tanda`,
+		`{\move(0,0,100,100,500,1500)\clip(m 10 10 l 10 20 20 20)}Gerak`,
+		`{\p1\pbo10}m 0 0 l 10 10{\p0}`,
+		`{\t(0,500,\fs80\t(\bord3))\clip(2, m 0 0 l}x`,
+		`{\clip(}{\iclip(1,2,3,4}{\pos(,)}{\move(1,2,3)}`,
+		`{\be-0.5\blur.5\shad-0\fsc-.\fscx1e9\pos(1.2.3,4)}`,
+		`{\1a&HFF&\k20\fad(100,200)\fade(1,2,3,4,5,6,7)}`,
+		`{ lone brace {\fs20} } x {`,
+		"{\\p1}m 0 0 l é 10 — 20{\\p0}",
+	} {
+		f.Add(seed)
+	}
+	one := resampler{rx: 1, ry: 1, rm: 1, ar: 1}
+	scaled := resampler{rx: 1.125, ry: 1.5, rm: math.Sqrt(1.125 * 1.5), ar: 0.75}
+	f.Fuzz(func(t *testing.T, text string) {
+		if got := one.eventText(text, ""); got != text {
+			t.Fatalf("rasio 1 mengubah teks:\n in  %q\n out %q", text, got)
+		}
+		out := scaled.eventText(text, "")
+		if utf8.ValidString(text) && !utf8.ValidString(out) {
+			t.Fatalf("output bukan UTF-8 valid: %q → %q", text, out)
+		}
+		scaled.eventText(out, "Arial")
+	})
+}
+
+// File yang sudah 1920x1080: teks event lolos byte demi byte, juga lewat
+// resampleLimenime yang tetap menyuntik font/style.
+func TestResample1080pIsNoOp(t *testing.T) {
+	withOpts(t)
+	events := []string{
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0010,10,20.0,,{\pos(100.000,0100)\fs20.0\bord 2\be1.5}a`,
+		`Dialogue: 0,0:00:03.00,0:00:04.00,Default,,0,0,0,,{\move(0,0, 1.50,2,0,500)\t(0,500,\fscx100.0)\clip(m 0 0 l ..0 5)}b`,
+		`Dialogue: 0,0:00:05.00,0:00:06.00,Default,,0,0,0,,{\p1}m 0 0 l 10.10 -0 {\c&H00FF00&}20{\p0}{ lone`,
+	}
+	src := miniASS(1920, 1080, miniStyle, events...)
+	out, err := ResampleASS(src, 1920, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != src {
+		t.Errorf("ResampleASS 1080p → 1080p mengubah isi:\n%s", out)
+	}
+	out, err = resampleLimenime(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dialogues(out); !reflect.DeepEqual(got, events) {
+		t.Errorf("resampleLimenime mengubah teks event:\n got %q\nwant %q", got, events)
+	}
+}
+
+// ======================================
+// 🔹 ConvertAny
+// ======================================
+
+// VTT/TTML/JSON dari memori: dengan hint dan tanpa hint (ditebak dari isi)
+// harus memberi ASS yang sama. Input tanpa cue adalah error, bukan path file.
+func TestConvertAny(t *testing.T) {
+	withOpts(t)
+	tests := []struct{ fixture, hint, format string }{
+		{"basic.vtt", "vtt", "vtt"},
+		{"basic.ttml", ".ttml", "ttml"},
+		{"bilibili.json", "JSON", "json"},
+		{"youtube.json", "json", "json"},
+		{"basic.srt", "srt", "srt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data := readFixture(t, tt.fixture)
+			withHint, format, err := ConvertAny(data, tt.hint)
+			if err != nil || format != tt.format {
+				t.Fatalf("hint %q: format %q, err %v", tt.hint, format, err)
+			}
+			sniffed, format, err := ConvertAny(data, "")
+			if err != nil || format != tt.format {
+				t.Fatalf("tanpa hint: format %q, err %v", format, err)
+			}
+			if withHint != sniffed {
+				t.Errorf("hasil dengan dan tanpa hint beda")
+			}
+			if len(dialogues(withHint)) == 0 {
+				t.Errorf("tidak ada Dialogue:\n%s", withHint)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ data, hint string }{
+		{"hello", "srt"},
+		{"hello", ""},
+		{"", "srt"},
+		{"WEBVTT\n\nNOTE kosong\n", ""},
+		{"testdata/basic.srt", "srt"}, // path file tidak pernah dibaca
+	} {
+		if out, _, err := ConvertAny([]byte(tt.data), tt.hint); err == nil {
+			t.Errorf("ConvertAny(%q, %q) harus error, dapat:\n%s", tt.data, tt.hint, out)
+		}
+	}
+}
+
+// ======================================
+// 🔹 Ekspor WebVTT
+// ======================================
+
+// \an8 jadi line:0, \an7 ditambah align:start, \pos jadi persen PlayRes, dan
+// style beralignment atas ikut terbawa tanpa tag.
+func TestWriteVTTPositioning(t *testing.T) {
+	top := strings.Replace(miniStyle, "Style: Default,", "Style: Atas,", 1)
+	top = strings.Replace(top, ",2,20,20,30,1", ",8,20,20,30,1", 1)
+	ass := miniASS(1920, 1080, miniStyle+"\n"+top,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\an8}Atas`,
+		`Dialogue: 0,0:00:03.00,0:00:04.50,Default,,0,0,0,,{\an7}Kiri atas`,
+		`Dialogue: 0,0:00:05.00,0:00:06.00,Default,,0,0,0,,{\pos(960,540)}Tengah`,
+		`Dialogue: 0,0:00:07.00,0:00:08.00,Atas,,0,0,0,,Style atas`,
+		`Dialogue: 0,0:00:09.00,0:00:10.00,Default,,0,0,0,,Bawah`,
+	)
+	want := "WEBVTT\n\n" +
+		"00:00:01.000 --> 00:00:02.000 line:0\nAtas\n\n" +
+		"00:00:03.000 --> 00:00:04.500 line:0 align:start\nKiri atas\n\n" +
+		"00:00:05.000 --> 00:00:06.000 line:50% position:50%\nTengah\n\n" +
+		"00:00:07.000 --> 00:00:08.000 line:0\nStyle atas\n\n" +
+		"00:00:09.000 --> 00:00:10.000\nBawah\n\n"
+	if got := writeVTT(parseASSCues(ass)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// ======================================
+// 🔹 TTML timecode frame
+// ======================================
+
+// ttp:frameRate="24" dipakai tanpa -fps: frame 12 = 0,5 detik. Dengan
+// -fps-detect=false jatuh ke default 25 fps.
+func TestTTMLFrameRateDetect(t *testing.T) {
+	withOpts(t)
+	ttml := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" ttp:frameRate="24" ttp:timeBase="smpte">
+  <body><div>
+    <p begin="00:00:01:12" end="00:00:02:00">Frame dua empat</p>
+  </div></body>
+</tt>`)
+	srt, err := ttmlToSRT(ttml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(srt, "00:00:01,500 --> 00:00:02,000") {
+		t.Errorf("24 fps dari metadata tidak dipakai:\n%s", srt)
+	}
+
+	opts.FPSDetect = false
+	srt, err = ttmlToSRT(ttml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(srt, "00:00:01,480 --> 00:00:02,000") {
+		t.Errorf("tanpa -fps-detect harus default 25 fps:\n%s", srt)
+	}
+}
+
+// Blok NOTE (multi-baris sampai baris kosong) di antara cue dibuang; identifier
+// angka maupun teks hanya dianggap identifier jika baris berikutnya timing.
+func TestVTTNoteAndIdentifiers(t *testing.T) {
+	withOpts(t)
+	vtt := "WEBVTT\n\n" +
+		"1\n00:00:01.000 --> 00:00:02.000\nSatu\n\n" +
+		"NOTE ini komentar\nmasih komentar 00:00:03.000 --> 00:00:04.000\n\n" +
+		"intro lagu\n00:00:05.000 --> 00:00:06.000\nDua\n42\n\n" +
+		"NOTE\nbaris tunggal\n\n" +
+		"00:00:07.000 --> 00:00:08.000\nTiga\n"
+	srt, err := vttToSRT([]byte(vtt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:01,000 --> 00:00:02,000\nSatu\n\n" +
+		"2\n00:00:05,000 --> 00:00:06,000\nDua\n42\n\n" +
+		"3\n00:00:07,000 --> 00:00:08,000\nTiga\n\n"
+	if srt != want {
+		t.Errorf("got:\n%q\nwant:\n%q", srt, want)
+	}
+}
+
+// ======================================
+// 🔹 Custom XML: satuan waktu
+// ======================================
+
+// customDia: Custom XML berisi satu <dia> per pasangan st/et.
+func customDia(times ...[2]string) []byte {
+	var sb strings.Builder
+	sb.WriteString("<xml>")
+	for i, tm := range times {
+		fmt.Fprintf(&sb, "<dia><st>%s</st><et>%s</et><sub>cue %d</sub></dia>", tm[0], tm[1], i+1)
+	}
+	sb.WriteString("</xml>")
+	return []byte(sb.String())
+}
+
+// captureStderr menjalankan fn dan mengembalikan yang ditulisnya ke os.Stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = old }()
+	fn()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestCustomXMLTimeUnits(t *testing.T) {
+	tests := []struct {
+		name, unit string
+		data       []byte
+		want       string
+	}{
+		{"auto cs", "auto", customDia([2]string{"100", "250"}), "00:00:01,000 --> 00:00:02,500"},
+		{"auto ms", "auto", customDia([2]string{"1000", "2500"}), "00:00:01,000 --> 00:00:02,500"},
+		{"jam", "auto", customDia([2]string{"00:00:01.000", "00:00:02.500"}), "00:00:01,000 --> 00:00:02,500"},
+		{"override s", "s", customDia([2]string{"1", "2.5"}), "00:00:01,000 --> 00:00:02,500"},
+		{"override cs", "cs", customDia([2]string{"1000", "2500"}), "00:00:10,000 --> 00:00:25,000"},
+		{"override ms", "ms", customDia([2]string{"100", "250"}), "00:00:00,100 --> 00:00:00,250"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOpts(t)
+			opts.TimeUnit = tt.unit
+			srt, err := customXMLToSRT(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(srt, "\n"+tt.want+"\n") {
+				t.Errorf("got:\n%s\nwant timing %s", srt, tt.want)
+			}
+		})
+	}
+}
+
+// Nilai <et> rusak: cue dilewati dan peringatannya menyebut alasannya (bukan
+// "<nil>"); cue lain tetap ditulis.
+func TestCustomXMLBadTime(t *testing.T) {
+	withOpts(t)
+	var srt string
+	var err error
+	stderr := captureStderr(t, func() {
+		srt, err = customXMLToSRT(customDia([2]string{"100", "abc"}, [2]string{"300", "400"}))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr, `<dia> ke-1 dilewati: waktu "abc" tidak valid`) {
+		t.Errorf("peringatan salah: %q", stderr)
+	}
+	if strings.Contains(srt, "cue 1") || !strings.Contains(srt, "1\n00:00:03,000 --> 00:00:04,000\ncue 2") {
+		t.Errorf("got:\n%s", srt)
+	}
+}
+
+// ======================================
+// 🔹 Teks cue: entity & markup
+// ======================================
+
+// Markup yang di-escape di dalam cue tidak boleh merusak parse XML: unescape
+// dalam dilakukan per teks setelah parse, bukan pada dokumen mentah.
+func TestEscapedMarkupInCueBody(t *testing.T) {
+	withOpts(t)
+	ttml := `<tt xmlns="http://www.w3.org/ns/ttml"><body><div>
+<p begin="00:00:01.000" end="00:00:02.000">Tulis &lt;p&gt;halo&lt;/p&gt;</p>
+<p begin="00:00:03.000" end="00:00:04.000">Kedua</p>
+</div></body></tt>`
+	srt, err := ttmlToSRT([]byte(ttml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nTulis <p>halo</p>\n\n2\n00:00:03,000 --> 00:00:04,000\nKedua\n\n"; srt != want {
+		t.Errorf("TTML got %q, want %q", srt, want)
+	}
+
+	custom := `<xml><dia><st>100</st><et>200</et><sub><![CDATA[Contoh &lt;p&gt;halo&lt;/p&gt;]]></sub></dia>` +
+		`<dia><st>300</st><et>400</et><sub>A &lt;i&gt;b&lt;/i&gt;</sub></dia></xml>`
+	srt, err = customXMLToSRT([]byte(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nContoh <p>halo</p>\n\n2\n00:00:03,000 --> 00:00:04,000\nA <i>b</i>\n\n"; srt != want {
+		t.Errorf("Custom XML got %q, want %q", srt, want)
+	}
+}
+
+// -preserve-spacing: spasi beruntun dipertahankan, spasi awal jadi \h supaya
+// tidak dibuang renderer; tanpa flag spasi diringkas seperti biasa.
+func TestPreserveSpacing(t *testing.T) {
+	withOpts(t)
+	sign := "  KIRI    KANAN  "
+	if got := convertTagsToASS(sign); got != "KIRI KANAN" {
+		t.Errorf("tanpa flag got %q", got)
+	}
+	opts.PreserveSpacing = true
+	if got := convertTagsToASS(sign); got != `\h\hKIRI    KANAN` {
+		t.Errorf("-preserve-spacing got %q", got)
+	}
+}
+
+// &nbsp; di awal baris (entity maupun U+00A0 hasil decode) jadi \h.
+func TestLeadingNbspIndent(t *testing.T) {
+	withOpts(t)
+	for _, in := range []string{"&nbsp;&nbsp;Indent", "\u00a0\u00a0Indent"} {
+		if got := convertTagsToASS(in); got != `\h\hIndent` {
+			t.Errorf("%q: got %q", in, got)
+		}
+	}
+}
+
+// Tag ASS {\c...} yang sudah ada di teks dibiarkan utuh di samping <i>.
+func TestExistingASSColorNextToItalic(t *testing.T) {
+	withOpts(t)
+	got := convertTagsToASS(`<i>miring</i> {\c&HFF0000&}biru`)
+	if want := `{\i1}miring{\i0} {\c&HFF0000&}biru`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Nama warna HTML dipetakan ke BGR ASS; nama tak dikenal membuang tag saja.
+func TestFontNamedColors(t *testing.T) {
+	withOpts(t)
+	cases := map[string]string{
+		`<font color="red">m</font>`:       `{\c&H0000FF&}m`,
+		`<font color="#00FF00">h</font>`:   `{\c&H00FF00&}h`,
+		`<font color="ungu-aneh">x</font>`: `x`,
+	}
+	for in, want := range cases {
+		if got := convertTagsToASS(in); got != want {
+			t.Errorf("%s: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Hex pendek, hex dengan alpha (#RRGGBBAA → \1a terbalik) dan putih.
+func TestFontHexColorForms(t *testing.T) {
+	withOpts(t)
+	cases := map[string]string{
+		`<font color="#f00">a</font>`:      `{\c&H0000FF&}a`,
+		`<font color="#ff000080">b</font>`: `{\c&H0000FF&\1a&H7F&}b`,
+		`<font color="#ffffff">c</font>`:   `{\c&HFFFFFF&}c`,
+	}
+	for in, want := range cases {
+		if got := convertTagsToASS(in); got != want {
+			t.Errorf("%s: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// -annotate: tiap Dialogue didahului Comment: berisi nomor & timing cue sumber.
+func TestAnnotate(t *testing.T) {
+	withOpts(t)
+	opts.Annotate = true
+	srt := "1\r\n00:00:01,000 --> 00:00:02,000\r\nHalo\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,500\r\nDunia\r\n"
+	lines := dialogues(processSRT(srt))
+	want := []string{
+		"Comment: 0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,,src #1 00:00:01,000 --> 00:00:02,000",
+		"Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,," + defaultDialogueFX + "Halo",
+		"Comment: 0,0:00:03.00,0:00:04.50,Default,,0000,0000,0000,,src #2 00:00:03,000 --> 00:00:04,500",
+		"Dialogue: 0,0:00:03.00,0:00:04.50,Default,,0000,0000,0000,," + defaultDialogueFX + "Dunia",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// ======================================
+// 🔹 Validasi cue
+// ======================================
+
+// Baris timing rusak dilaporkan; defaultnya waktu jadi nol, -strict membuang cue-nya.
+func TestMalformedTimingStrict(t *testing.T) {
+	withOpts(t)
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nok\n\n2\n00:00:xx,000 --> 00:00:04,000\nrusak\n\n3\n00:00:05,000 --> 00:00:06,000\ntiga\n"
+	for _, strict := range []bool{false, true} {
+		opts.Strict = strict
+		out, report := processSRTReport([]byte(srt))
+		if !reflect.DeepEqual(report.BadTiming, []int{6}) {
+			t.Errorf("strict=%v: BadTiming = %v, want [6]", strict, report.BadTiming)
+		}
+		var texts []string
+		for _, l := range dialogues(out) {
+			texts = append(texts, strings.TrimPrefix(dialogueText(l), defaultDialogueFX))
+		}
+		want := []string{"rusak", "ok", "tiga"}
+		if strict {
+			want = []string{"ok", "tiga"}
+		}
+		if !reflect.DeepEqual(texts, want) {
+			t.Errorf("strict=%v: got %q, want %q", strict, texts, want)
+		}
+	}
+	if s := (cueReport{BadTiming: []int{6}}).summary(); !strings.Contains(s, "cue-nya dibuang (baris 6)") {
+		t.Errorf("summary -strict: %q", s)
+	}
+}
+
+// end<start dan end==start selalu dibuang; cue pendek hanya dengan -min-duration.
+func TestValidateCueMinDuration(t *testing.T) {
+	withOpts(t)
+	srt := "1\n00:00:02,000 --> 00:00:01,000\nneg\n\n2\n00:00:03,000 --> 00:00:03,000\nnol\n\n" +
+		"3\n00:00:04,000 --> 00:00:04,200\npendek\n\n4\n00:00:05,000 --> 00:00:06,000\nok\n"
+	for _, tc := range []struct {
+		min     time.Duration
+		dropped int
+		kept    int
+	}{
+		{0, 2, 2},
+		{500 * time.Millisecond, 3, 1},
+	} {
+		opts.MinDuration = tc.min
+		out, report := processSRTReport([]byte(srt))
+		if report.Dropped != tc.dropped || len(dialogues(out)) != tc.kept {
+			t.Errorf("min=%v: dropped %d kept %d, want %d/%d", tc.min, report.Dropped, len(dialogues(out)), tc.dropped, tc.kept)
+		}
+	}
+	if err := validateCue(4000, 4200); err == nil || !strings.Contains(err.Error(), "-min-duration") {
+		t.Errorf("validateCue(200ms) = %v", err)
+	}
+}
+
+// -fix-times menukar start/end yang terbalik, mempertahankan suffix dan CRLF.
+func TestFixSwappedTimes(t *testing.T) {
+	var got string
+	stderr := captureStderr(t, func() {
+		got = fixSwappedTimes("1\r\n00:00:04,000 --> 00:00:02,500 X1:1\r\nswap\r\n\r\n2\r\n00:00:05,000 --> 00:00:06,000\r\nok\r\n")
+	})
+	if want := "1\r\n00:00:02,500 --> 00:00:04,000 X1:1\r\nswap\r\n\r\n2\r\n00:00:05,000 --> 00:00:06,000\r\nok\r\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !strings.Contains(stderr, "baris 2: start 00:00:04,000 > end 00:00:02,500") {
+		t.Errorf("peringatan salah: %q", stderr)
+	}
+}
+
+// ======================================
+// 🔹 Helper SRT → ASS
+// ======================================
+
+func TestSRTTimeHelpers(t *testing.T) {
+	cases := []struct {
+		in  string
+		ass string
+		ms  int
+	}{
+		{"00:01:02,345", "0:01:02.34", 62345}, // centidetik dipotong, bukan dibulatkan
+		{" 10:00:00,005 ", "10:00:00.00", 36000005},
+		{"123:00:00,000", "123:00:00.00", 442800000},
+		{"1:02:03.999", "", 0}, // SRT memakai koma
+		{"00:00:xx,000", "", 0},
+		{"", "", 0},
+	}
+	for _, c := range cases {
+		got, err := srtTimeToASS(c.in)
+		if got != c.ass || (err != nil) != (c.ass == "") {
+			t.Errorf("srtTimeToASS(%q) = %q, %v; want %q", c.in, got, err, c.ass)
+		}
+		if ms := srtTimeToMs(c.in); ms != c.ms {
+			t.Errorf("srtTimeToMs(%q) = %d, want %d", c.in, ms, c.ms)
+		}
+	}
+}
+
+func TestDefineStyle(t *testing.T) {
+	withOpts(t)
+	cases := map[string]string{
+		"Halo":              "Default",
+		"(Suara pintu)":     "tanda",
+		"[MUSIK]":           "tanda",
+		`{\i1}(bisik){\i0}`: "tanda",
+		"TOKO BUKU":         "tanda",
+		"♪ lagu ♪":          "song",
+	}
+	for in, want := range cases {
+		if got := defineStyle(in, 2000); got != want {
+			t.Errorf("defineStyle(%q) = %s, want %s", in, got, want)
+		}
+	}
+	opts.NoTanda = true
+	if got := defineStyle("(Suara pintu)", 2000); got != "Default" {
+		t.Errorf("-no-tanda: got %s", got)
+	}
+}
+
+// -default-fx mengganti override di depan baris Default saja; -no-default-fx
+// (DefaultFX kosong) menulis teks tanpa prefix. Baris tanda tidak tersentuh.
+func TestDefaultFX(t *testing.T) {
+	withOpts(t)
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nHalo\n\n2\n00:00:03,000 --> 00:00:04,000\n(Suara pintu)\n"
+	for _, fx := range []string{defaultDialogueFX, `{\be1}`, ""} {
+		opts.DefaultFX = fx
+		var texts []string
+		for _, l := range dialogues(processSRT(srt)) {
+			texts = append(texts, dialogueText(l))
+		}
+		sort.Strings(texts)
+		if want := []string{"(Suara pintu)", fx + "Halo"}; !reflect.DeepEqual(texts, want) {
+			t.Errorf("fx=%q: got %q, want %q", fx, texts, want)
+		}
+	}
+}
+
+// ======================================
+// 🔹 Arsip zip & batch
+// ======================================
+
+// Entri .txt dilewati, entri di subfolder dan entri "../" ditulis dengan
+// base name-nya saja di dalam outDir.
+func TestConvertZipArchive(t *testing.T) {
+	withOpts(t)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range []struct{ name, body string }{
+		{"sub/a.srt", "1\r\n00:00:01,000 --> 00:00:02,000\r\nHalo\r\n"},
+		{"readme.txt", "baca saya"},
+		{"../../jahat.srt", "1\r\n00:00:03,000 --> 00:00:04,000\r\nJahat\r\n"},
+	} {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	outDir := filepath.Join(root, "out")
+	written, notes, err := convertZipArchive(zr, outDir, &phaseTimer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("notes: %q", notes)
+	}
+	want := []string{filepath.Join(outDir, "a_Limenime.ass"), filepath.Join(outDir, "jahat_Limenime.ass")}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("written %q, want %q", written, want)
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 || entries[0].Name() != "out" {
+		t.Errorf("ada file di luar outDir: %v", entries)
+	}
+	if data, _ := os.ReadFile(want[1]); !strings.Contains(string(data), "Jahat") {
+		t.Errorf("isi entri ../ salah:\n%s", data)
+	}
+}
+
+// Referensi angka hex, desimal, dan tanpa titik koma.
+func TestDeepUnescapeNumericRefs(t *testing.T) {
+	cases := map[string]string{
+		"&#x1F600;":  "\U0001F600",
+		"caf&#233;":  "café",
+		"&#160text":  "\u00a0text",
+		"&amp;#233;": "é",
+	}
+	for in, want := range cases {
+		if got := deepUnescapeHTML(in); got != want {
+			t.Errorf("deepUnescapeHTML(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// &amp; berlapis lebih dari maxUnescapePasses berhenti di batas: sisa lapisnya
+// tetap ada, tidak di-decode sampai habis.
+func TestDeepUnescapePassCap(t *testing.T) {
+	in := "&" + strings.Repeat("amp;", maxUnescapePasses+2) + "lt;"
+	want := "&" + strings.Repeat("amp;", 2) + "lt;"
+	if got := deepUnescapeHTML(in); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := deepUnescapeHTML("&amp;amp;lt;"); got != "<" {
+		t.Errorf("escape ganda: got %q", got)
+	}
+}
+
+// Beberapa input dengan base name sama dikonversi paralel: tiap worker harus
+// mendapat nama output sendiri (reserveOutputName), tidak saling menimpa.
+func TestConvertBatchSharedBaseName(t *testing.T) {
+	withOpts(t)
+	dir := t.TempDir()
+	srt := "1\r\n00:00:01,000 --> 00:00:02,000\r\nHalo\r\n"
+	vtt := "WEBVTT\r\n\r\n00:00:01.000 --> 00:00:02.000\r\nHalo\r\n"
+	var inputs []string
+	for name, body := range map[string]string{"ep.srt": srt, "ep.SRT": srt, "ep.Srt": srt, "ep.vtt": vtt} {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, p)
+	}
+
+	seen := map[string]bool{}
+	for _, res := range convertBatch(inputs, len(inputs), &phaseTimer{}) {
+		if res.Err != nil {
+			t.Fatalf("%s: %v", res.Input, res.Err)
+		}
+		if len(res.Outputs) != 1 {
+			t.Fatalf("%s: outputs %q", res.Input, res.Outputs)
+		}
+		out := res.Outputs[0]
+		if seen[out] {
+			t.Errorf("output %s dipakai dua kali", out)
+		}
+		seen[out] = true
+		if data, err := os.ReadFile(out); err != nil || !strings.Contains(string(data), "Halo") {
+			t.Errorf("%s: isi salah (%v)", out, err)
+		}
+	}
+}
+
+// RLM di awal tiap baris (setelah override), tidak dobel bila sudah ada; cue
+// Latin tidak dideteksi RTL, kecuali dipaksa -rtl.
+func TestRTLMarks(t *testing.T) {
+	withOpts(t)
+	if got, want := markRTL(`مرحبا!\N{\i1}עולם.`), rlm+`مرحبا!\N{\i1}`+rlm+"עולם."; got != want {
+		t.Errorf("markRTL got %q, want %q", got, want)
+	}
+	if got := markRTL(rlm + "مرحبا"); got != rlm+"مرحبا" {
+		t.Errorf("RLM dobel: %q", got)
+	}
+	if isRTLText("Halo!") || !isRTLText(`{\i1}مرحبا ok`) {
+		t.Error("deteksi RTL salah")
+	}
+
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nHalo!\n"
+	if text := dialogueText(dialogues(processSRT(srt))[0]); strings.Contains(text, rlm) {
+		t.Errorf("cue Latin diberi RLM: %q", text)
+	}
+	opts.RTL, opts.RTLq2 = true, true
+	if text := dialogueText(dialogues(processSRT(srt))[0]); !strings.HasSuffix(text, `{\q2}`+rlm+"Halo!") {
+		t.Errorf("-rtl -rtl-q2 got %q", text)
+	}
+}
+
+// Transcript: diurutkan menurut waktu, override/drawing/tag HTML dibuang, baris
+// kembar berurutan ditulis sekali kecuali dengan timestamp.
+func TestWriteTranscript(t *testing.T) {
+	doc := assDoc{Cues: []Cue{
+		{Start: 3000, End: 4000, Text: `{\i1}Dua{\i0}\Nbaris`},
+		{Start: 1000, End: 2000, Text: "Satu"},
+		{Start: 2000, End: 3000, Text: "Satu"},
+		{Start: 5000, End: 6000, Text: `{\p1}m 0 0 l 10 10{\p0}`},
+		{Start: 6000, End: 7000, Text: "<b>Tiga</b>"},
+	}}
+	if got, want := writeTranscript(doc, false), "Satu\nDua baris\nTiga\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	want := "[00:00:01.000] Satu\n[00:00:02.000] Satu\n[00:00:03.000] Dua baris\n[00:00:06.000] Tiga\n"
+	if got := writeTranscript(doc, true); got != want {
+		t.Errorf("timestamp: got %q, want %q", got, want)
+	}
+}
+
+// joinSRT: file kedua digeser ke akhir file pertama (atau offset eksplisit),
+// BOM/CRLF dibuang, dan nomor cue diurutkan ulang.
+func TestJoinSRT(t *testing.T) {
+	a := "1\r\n00:00:01,000 --> 00:00:02,000\r\nA\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nB\r\n"
+	b := string(rune(0xFEFF)) + "1\n00:00:00,500 --> 00:00:01,000\nC\n\n2\n00:00:02,000 --> 00:00:03,000\nD\n"
+	for _, tc := range []struct {
+		shifts []int
+		want   []string
+	}{
+		{nil, []string{"3\n00:00:04,500 --> 00:00:05,000\nC\n", "4\n00:00:06,000 --> 00:00:07,000\nD\n"}},
+		{[]int{10000}, []string{"3\n00:00:10,500 --> 00:00:11,000\nC\n", "4\n00:00:12,000 --> 00:00:13,000\nD\n"}},
+	} {
+		got := joinSRT([]string{a, b}, tc.shifts)
+		want := append([]string{"1\n00:00:01,000 --> 00:00:02,000\nA\n", "2\n00:00:03,000 --> 00:00:04,000\nB\n"}, tc.want...)
+		for _, w := range want {
+			if !strings.Contains(got, w) {
+				t.Errorf("shifts %v: tidak ada %q di\n%s", tc.shifts, w, got)
+			}
+		}
+		if strings.ContainsAny(got, "\r\ufeff") {
+			t.Errorf("shifts %v: CR/BOM tersisa: %q", tc.shifts, got)
+		}
+	}
+}
+
+// Cue yang melintasi titik potong masuk ke kedua bagian dengan waktu dijepit;
+// titik potong sebelum cue pertama menghasilkan before kosong.
+func TestSplitSRT(t *testing.T) {
+	srt := "1\r\n00:00:01,000 --> 00:00:02,000\r\nSatu\r\n\r\n" +
+		"2\r\n00:00:04,000 --> 00:00:06,000\r\nDua\r\nlintas\r\n\r\n" +
+		"3\r\n00:00:07,000 --> 00:00:08,000\r\nTiga\r\n"
+	before, after := splitSRT(srt, 5000)
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nSatu\n\n2\n00:00:04,000 --> 00:00:05,000\nDua\nlintas\n\n"; before != want {
+		t.Errorf("before got %q, want %q", before, want)
+	}
+	if want := "1\n00:00:00,000 --> 00:00:01,000\nDua\nlintas\n\n2\n00:00:02,000 --> 00:00:03,000\nTiga\n\n"; after != want {
+		t.Errorf("after got %q, want %q", after, want)
+	}
+
+	before, after = splitSRT(srt, 500)
+	if before != "" || !strings.HasPrefix(after, "1\n00:00:00,500 --> 00:00:01,500\nSatu\n") {
+		t.Errorf("potong di depan: before %q, after %q", before, after)
+	}
+	// bagian kosong tidak boleh dibaca sebagai path
+	if out, _ := processSRTReport([]byte(before)); len(dialogues(out)) != 0 {
+		t.Errorf("bagian kosong menghasilkan Dialogue: %q", dialogues(out))
+	}
+}
+
+// .srt kosong menghasilkan ASS tanpa Dialogue, bukan panic.
+func TestConvertFileEmptySRT(t *testing.T) {
+	withOpts(t)
+	path := filepath.Join(t.TempDir(), "kosong.srt")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, _, err := convertFile(path, &phaseTimer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "[Events]") || len(dialogues(out)) != 0 {
+		t.Errorf("got:\n%s", out)
+	}
+}
+
+// ======================================
+// 🔹 Remap waktu (-sync, -retime)
+// ======================================
+
+// Dua titik -sync: kedua titik acuan tepat, cue di antaranya diinterpolasi linear.
+func TestSyncRemap(t *testing.T) {
+	scale, offset, err := syncRemap([]syncPoint{{10000, 10400}, {600000, 625400}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srt := "1\r\n00:00:10,000 --> 00:05:00,000\r\nA\r\n\r\n2\r\n00:10:00,000 --> 00:10:01,000\r\nB\r\n"
+	want := "1\r\n00:00:10,400 --> 00:05:12,688\r\nA\r\n\r\n2\r\n00:10:25,400 --> 00:10:26,442\r\nB\r\n"
+	if got := remapTimes(srt, scale, offset); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if scale, offset, err := syncRemap([]syncPoint{{5000, 4000}}); err != nil || scale != 1 || offset != -1000 {
+		t.Errorf("satu titik: %v %v %v", scale, offset, err)
+	}
+	for _, bad := range [][]syncPoint{{}, {{1000, 2000}, {1000, 3000}}, {{1000, 5000}, {2000, 4000}}, {{1, 1}, {2, 2}, {3, 3}}} {
+		if _, _, err := syncRemap(bad); err == nil {
+			t.Errorf("syncRemap(%v) harus error", bad)
+		}
+	}
+}
+
+// Preset dan SRC:DST memberi rasio SRC/DST (NTSC persis); hasil remap dibulatkan
+// ke milidetik terdekat, bukan dipotong.
+func TestParseRetime(t *testing.T) {
+	for in, want := range map[string]float64{
+		"25to23.976": 25 / (24000.0 / 1001),
+		"23.976to25": (24000.0 / 1001) / 25,
+		" 24TO25 ":   0.96,
+		"30to29.97":  30 / (30000.0 / 1001),
+		"25:24":      25.0 / 24,
+	} {
+		got, err := parseRetime(in)
+		if err != nil || math.Abs(got-want) > 1e-12 {
+			t.Errorf("parseRetime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"25", "x:25", "25:0"} {
+		if _, err := parseRetime(bad); err == nil {
+			t.Errorf("parseRetime(%q) harus error", bad)
+		}
+	}
+
+	scale, _ := parseRetime("25to23.976")
+	// 1000 × 1.0427083 = 1042.7 → 1043; 2001 × 1.0427083 = 2086.46 → 2086
+	if got := remapTimes("00:00:01,000 --> 00:00:02,001\n", scale, 0); got != "00:00:01,043 --> 00:00:02,086\n" {
+		t.Errorf("pembulatan: got %q", got)
+	}
+}
+
+// Tag yang dibuka di satu baris cue dan ditutup di baris berikutnya diteruskan
+// ke tiap baris, jadi \N tidak memutus format.
+func TestCarryCueTags(t *testing.T) {
+	got := carryCueTags([]string{"<i>Baris satu", "baris dua</i>", "tiga"})
+	if want := []string{"<i>Baris satu</i>", "<i>baris dua</i>", "tiga"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = carryCueTags([]string{`<font color="red"><b>A`, "B</b> C</font>"})
+	if want := []string{`<font color="red"><b>A</b></font>`, `<font color="red"><b>B</b> C</font>`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bersarang: got %q, want %q", got, want)
+	}
+
+	withOpts(t)
+	text := dialogueText(dialogues(processSRT([]byte("1\n00:00:01,000 --> 00:00:02,000\n<i>Baris satu\nbaris dua</i>\n")))[0])
+	if want := `{\i1}Baris satu{\i0}\N{\i1}baris dua{\i0}`; !strings.HasSuffix(text, want) {
+		t.Errorf("ASS got %q, want akhiran %q", text, want)
+	}
+}
+
+// Tag salah sarang disusun ulang, tag yang tidak ditutup ditutup di akhir,
+// penutup yatim dan pasangan kosong dibuang.
+func TestNormalizeTags(t *testing.T) {
+	cases := map[string]string{
+		`<font color="red"><b>x</font></b>`: `<font color="red"><b>x</b></font>`,
+		"<b><i>a</b>b</i>":                  "<b><i>a</i></b><i>b</i>",
+		"<i>terbuka":                        "<i>terbuka</i>",
+		"yatim</b> teks":                    "yatim teks",
+		"<i></i>kosong":                     "kosong",
+	}
+	for in, want := range cases {
+		if got := normalizeTags(in); got != want {
+			t.Errorf("normalizeTags(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Dialek .sub ditentukan dari baris non-kosong pertama.
+func TestDetectSubDialect(t *testing.T) {
+	cases := map[string]string{
+		"{1}{1}23.976\n{10}{20}x":             "microdvd",
+		"\ufeff\n{10}{20}x":                   "microdvd",
+		"[INFORMATION]\n[TITLE]x":             "subviewer",
+		"00:00:01.00,00:00:02.00\nx":          "subviewer",
+		"1\n00:00:01,000 --> 00:00:02,000\nx": "",
+		"":                                    "",
+	}
+	for in, want := range cases {
+		if got := detectSubDialect(in); got != want {
+			t.Errorf("detectSubDialect(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if _, err := subToSRT([]byte("1\n00:00:01,000 --> 00:00:02,000\nx\n")); err == nil {
+		t.Error("SRT berekstensi .sub harus error")
+	}
+}
+
+// -flatten: semua Dialogue ke style Default tanpa margin dan override posisi;
+// drawing dijadikan Comment, baris Comment yang sudah ada tidak disentuh.
+func TestFlattenASS(t *testing.T) {
+	withOpts(t)
+	in := miniASS(1920, 1080, miniStyle,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,tanda,,10,20,30,,{\an8\pos(960,50)}Atas`,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\move(1,2,3,4)\i1\clip(0,0,10,10)}Miring, koma`,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\p1}m 0 0 l 10 10{\p0}`,
+		`Comment: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\pos(1,1)}catatan`)
+	want := []string{
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,,Atas`,
+		`Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,,{\i1}Miring, koma`,
+		`Comment: 0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,,{\p1}m 0 0 l 10 10{\p0}`,
+		`Comment: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\pos(1,1)}catatan`,
+	}
+	if got := dialogues(flattenASS(in)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+// Atribut color berkutip ganda/tunggal/tanpa kutip dibaca sama; hex RGB ditulis
+// sebagai BGR ASS, hex tidak valid ditolak.
+func TestHexColorAttr(t *testing.T) {
+	for _, tag := range []string{`<font color="#123456">`, `<font color='#123456'>`, `<font color=#123456>`, `<FONT COLOR = "#123456">`} {
+		if got := extractColorAttr(tag); got != "#123456" {
+			t.Errorf("extractColorAttr(%s) = %q", tag, got)
+		}
+	}
+	for in, want := range map[string]string{"#123456": "123456", "abc": "AABBCC", "#ff000080": "FF000080"} {
+		if got, ok := normalizeHexColor(in); !ok || got != want {
+			t.Errorf("normalizeHexColor(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"#12345", "#zzzzzz", "", "#1234567"} {
+		if got, ok := normalizeHexColor(bad); ok {
+			t.Errorf("normalizeHexColor(%q) = %q, harus ditolak", bad, got)
+		}
+	}
+	withOpts(t)
+	if got := convertTagsToASS(`<font color="#123456">x</font>`); got != `{\c&H563412&}x` {
+		t.Errorf("convertTagsToASS got %q", got)
+	}
+}
+
+// .srt.gz dibaca transparan, formatnya dari ekstensi sebelum .gz, dan nama
+// output tidak membawa .srt/.gz.
+func TestGzipSRTInput(t *testing.T) {
+	withOpts(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ep01.srt.gz")
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("1\r\n00:00:01,000 --> 00:00:02,000\r\nHalo\r\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if ext := inputExt(path); ext != ".srt" {
+		t.Errorf("inputExt = %q, want .srt", ext)
+	}
+	out, _, err := convertFile(path, &phaseTimer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := dialogues(out); len(lines) != 1 || !strings.HasSuffix(lines[0], "Halo") {
+		t.Errorf("got %q", lines)
+	}
+	if name := generateOutputName(path, ".ass"); name != filepath.Join(dir, "ep01_Limenime.ass") {
+		t.Errorf("generateOutputName = %q", name)
+	}
+
+	if _, err := readInput(filepath.Join(dir, "rusak.srt.gz")); err == nil {
+		t.Error("file tidak ada harus error")
+	}
+	bad := filepath.Join(dir, "bukan.srt.gz")
+	os.WriteFile(bad, []byte("bukan gzip"), 0644)
+	if _, err := readInput(bad); err == nil || !strings.Contains(err.Error(), "gzip") {
+		t.Errorf("gzip rusak: %v", err)
+	}
+}
+
+// ======================================
+// 🔹 Deteksi format dari isi
+// ======================================
+
+func TestSniffFormat(t *testing.T) {
+	cases := map[string]string{
+		"\ufeffWEBVTT\n\n00:00:01.000 --> 00:00:02.000\nx":                 "vtt",
+		"[Script Info]\nScriptType: v4.00+":                                "ass",
+		`<?xml version="1.0"?><tt xmlns="http://www.w3.org/ns/ttml"></tt>`: "ttml",
+		"<?xml version=\"1.0\"?>\n<xml><dia><st>1</st></dia></xml>":        "xml",
+		`{"body":[{"from":1,"to":2,"content":"x"}]}`:                       "json",
+		"{1}{1}25\n{25}{50}x":                                              "sub",
+		"[INFORMATION]\n[TITLE]x":                                          "sub",
+		"\n\n1\n00:00:01,000 --> 00:00:02,000\nx":                          "srt",
+		"teks biasa tanpa timing":                                          "",
+	}
+	for in, want := range cases {
+		if got := sniffFormat([]byte(in)); got != want {
+			t.Errorf("sniffFormat(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// File tanpa ekstensi dibaca dan ditebak formatnya sekali saja.
+func TestConvertFileSniffsOnce(t *testing.T) {
+	withOpts(t)
+	opts.Verbose = true
+	path := filepath.Join(t.TempDir(), "caption")
+	if err := os.WriteFile(path, []byte("WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHalo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out string
+	var err error
+	stderr := captureStderr(t, func() { out, _, err = convertFile(path, &phaseTimer{}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(stderr, "dikenali dari isinya sebagai vtt"); n != 1 {
+		t.Errorf("format ditebak %d kali:\n%s", n, stderr)
+	}
+	if lines := dialogues(out); len(lines) != 1 || !strings.HasSuffix(lines[0], "Halo") {
+		t.Errorf("got %q", lines)
+	}
+}
+
+// VTT ber-CRLF, CR saja (Mac lama), dan LF menghasilkan SRT yang sama.
+func TestVTTLineEndings(t *testing.T) {
+	if got := normalizeEOL("a\r\nb\rc\n\r\nd"); got != "a\nb\nc\n\nd" {
+		t.Errorf("normalizeEOL got %q", got)
+	}
+	want := "1\n00:00:01,000 --> 00:00:02,000\nSatu\ndua\n\n2\n00:00:03,000 --> 00:00:04,000\nTiga\n\n"
+	for name, eol := range map[string]string{"CRLF": "\r\n", "CR": "\r", "LF": "\n"} {
+		vtt := strings.Join([]string{"WEBVTT", "", "00:00:01.000 --> 00:00:02.000", "Satu", "dua", "",
+			"00:00:03.000 --> 00:00:04.000", "Tiga", ""}, eol)
+		got, err := vttToSRT([]byte(vtt))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TTML multibahasa: tanpa -lang bahasa pertama dipakai dengan peringatan;
+// -lang memilih bahasa (termasuk subtag seperti en → en-US); bahasa yang tidak
+// ada menghasilkan ttmlLangError, tidak jatuh ke parser XML generik.
+func TestTTMLLangFilter(t *testing.T) {
+	withOpts(t)
+	data := readFixture(t, "multilang.ttml")
+
+	var srt string
+	var err error
+	stderr := captureStderr(t, func() { srt, err = srtFromData(data, "ttml") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n"; srt != want {
+		t.Errorf("default got %q, want %q", srt, want)
+	}
+	if !strings.Contains(stderr, "TTML berisi 3 bahasa (en-US, id, id-ID), hanya en-US yang diambil") {
+		t.Errorf("peringatan salah: %q", stderr)
+	}
+
+	for lang, want := range map[string]string{
+		"en": "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n",
+		"ID": "1\n00:00:01,000 --> 00:00:02,000\nHalo\n\n2\n00:00:03,000 --> 00:00:04,000\nDunia\n\n",
+	} {
+		opts.Lang = lang
+		if srt, err := srtFromData(data, "ttml"); err != nil || srt != want {
+			t.Errorf("-lang %s: got %q, %v; want %q", lang, srt, err, want)
+		}
+	}
+
+	opts.Lang = "fr"
+	_, err = srtFromData(data, "ttml")
+	if _, ok := err.(ttmlLangError); !ok {
+		t.Fatalf("-lang fr: err = %v, want ttmlLangError", err)
+	}
+	if !strings.Contains(err.Error(), `"fr" tidak ada di TTML (tersedia: en-US, id, id-ID)`) {
+		t.Errorf("pesan error: %v", err)
+	}
+}
+
+// <br/> di dalam span dan <br/><br/> beruntun: baris kosongnya dijaga sebagai
+// U+00A0 (bukan entity mentah) supaya cue tidak terputus dan tidak ada &nbsp;
+// yang bocor ke output SRT, VTT, maupun transkrip.
+func TestTTMLDoubleBreakInSpans(t *testing.T) {
+	withOpts(t)
+	ttml := `<tt xmlns="http://www.w3.org/ns/ttml"><body><div>
+<p begin="00:00:01.000" end="00:00:02.000"><span>Satu<br/></span><br/><span>tiga</span></p>
+</div></body></tt>`
+	srt, err := ttmlToSRT([]byte(ttml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nSatu\n\u00a0\ntiga\n\n"; srt != want {
+		t.Errorf("SRT perantara got %q, want %q", srt, want)
+	}
+
+	clean, _ := cleanSRT(srt)
+	ass := processSRT([]byte(srt))
+	outputs := map[string]string{
+		"srt": clean,
+		"vtt": writeVTT(parseASSCues(ass)),
+		"txt": writeTranscript(parseASSCues(ass), false),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, "&nbsp;") {
+			t.Errorf("-to %s berisi &nbsp;: %q", name, out)
+		}
+	}
+	if clean != srt {
+		t.Errorf("cleanSRT memutus cue: %q", clean)
+	}
+	if lines := dialogues(ass); len(lines) != 1 || !strings.HasSuffix(lines[0], `Satu\N\h\Ntiga`) {
+		t.Errorf("ASS got %q", lines)
+	}
+}